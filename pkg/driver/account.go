@@ -0,0 +1,130 @@
+package driver
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type VipLevel int
+
+const (
+	VipLevelNone VipLevel = iota
+	// VipLevelExpired is an account whose VIP has expired.
+	VipLevelExpired
+	VipLevelVip
+	// VipLevelForever is a lifetime VIP.
+	VipLevelForever
+)
+
+func (l VipLevel) String() string {
+	switch l {
+	case VipLevelNone:
+		return "none"
+	case VipLevelExpired:
+		return "expired"
+	case VipLevelVip:
+		return "vip"
+	case VipLevelForever:
+		return "forever"
+	}
+	return "unknown"
+}
+
+// VipLevel returns the VIP level of the user.
+func (u *UserInfo) VipLevel() VipLevel {
+	switch {
+	case u.Forever == 1:
+		return VipLevelForever
+	case u.Vip > 0 && (u.Expire == 0 || time.Now().Before(u.VipExpire())):
+		return VipLevelVip
+	case u.Expire > 0:
+		return VipLevelExpired
+	}
+	return VipLevelNone
+}
+
+// VipExpire returns the expiry time of VIP, zero if the user is never a VIP or a lifetime VIP.
+func (u *UserInfo) VipExpire() time.Time {
+	if u.Expire <= 0 || u.Forever == 1 {
+		return time.Time{}
+	}
+	return time.Unix(int64(u.Expire), 0)
+}
+
+// Account combines user information, space, offline quota and upload limit of an account.
+type Account struct {
+	UserID   int64
+	UserName string
+	Face     string
+	VipLevel VipLevel
+	// VipExpire is zero if the user is never a VIP or a lifetime VIP.
+	VipExpire time.Time
+	// TotalSize, UsedSize and RemainSize are space in bytes.
+	TotalSize    int64
+	UsedSize     int64
+	RemainSize   int64
+	OfflineQuota *OfflineQuota
+	// UploadSizeLimit is the max size in bytes of a single upload.
+	UploadSizeLimit int64
+}
+
+// GetAccount fetches user information, space info, offline quota and upload info concurrently,
+// canceling ctx aborts all requests.
+func (c *Pan115Client) GetAccount(ctx context.Context) (*Account, error) {
+	var (
+		user  *UserInfo
+		info  InfoData
+		quota *OfflineQuota
+		meta  *UploadInfoResp
+		errs  [4]error
+		wg    sync.WaitGroup
+	)
+	calls := []func() error{
+		func() (err error) {
+			user, err = c.getUser(ctx)
+			return
+		},
+		func() (err error) {
+			info, err = c.getInfo(ctx)
+			return
+		},
+		func() (err error) {
+			quota, err = c.getOfflineQuota(ctx)
+			return
+		},
+		func() (err error) {
+			meta, err = c.getUploadInfo(ctx)
+			return
+		},
+	}
+	for i, call := range calls {
+		wg.Add(1)
+		go func(i int, call func() error) {
+			defer wg.Done()
+			errs[i] = call()
+		}(i, call)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Account{
+		UserID:          user.UserID,
+		UserName:        user.UserName,
+		Face:            user.Face,
+		VipLevel:        user.VipLevel(),
+		VipExpire:       user.VipExpire(),
+		TotalSize:       info.SpaceInfo.AllTotal.Size,
+		UsedSize:        info.SpaceInfo.AllUse.Size,
+		RemainSize:      info.SpaceInfo.AllRemain.Size,
+		OfflineQuota:    quota,
+		UploadSizeLimit: meta.SizeLimit,
+	}, nil
+}
@@ -0,0 +1,313 @@
+package driver
+
+import (
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AccountPool manages clients of multiple accounts keyed by UserID.
+// An account which fails is skipped for a cooldown, so one broken account does not break the pool.
+type AccountPool struct {
+	opts    *AccountPoolOptions
+	mu      sync.Mutex
+	ids     []int64
+	clients map[int64]*Pan115Client
+	health  map[int64]*accountHealth
+}
+
+type accountHealth struct {
+	failures int
+	until    time.Time
+	lastErr  error
+}
+
+// NewAccountPool creates an empty account pool.
+func NewAccountPool(opts ...AccountPoolOption) *AccountPool {
+	o := DefaultAccountPoolOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &AccountPool{
+		opts:    o,
+		clients: map[int64]*Pan115Client{},
+		health:  map[int64]*accountHealth{},
+	}
+}
+
+// Add adds a client to the pool, it replaces the client of the same user.
+func (p *AccountPool) Add(c *Pan115Client) error {
+	if err := c.ensureUserID(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.clients[c.UserID]; !ok {
+		p.ids = append(p.ids, c.UserID)
+	}
+	p.clients[c.UserID] = c
+	p.health[c.UserID] = &accountHealth{}
+	return nil
+}
+
+// Remove removes the client of userID from the pool.
+func (p *AccountPool) Remove(userID int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.clients[userID]; !ok {
+		return
+	}
+	delete(p.clients, userID)
+	delete(p.health, userID)
+	for i, id := range p.ids {
+		if id == userID {
+			p.ids = append(p.ids[:i], p.ids[i+1:]...)
+			break
+		}
+	}
+}
+
+// Get returns the client of userID.
+func (p *AccountPool) Get(userID int64) (*Pan115Client, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c, ok := p.clients[userID]
+	return c, ok
+}
+
+// Clients returns all clients in order of adding.
+func (p *AccountPool) Clients() []*Pan115Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	clients := make([]*Pan115Client, 0, len(p.ids))
+	for _, id := range p.ids {
+		clients = append(clients, p.clients[id])
+	}
+	return clients
+}
+
+// Available returns clients which are not cooling down after a failure.
+func (p *AccountPool) Available() []*Pan115Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	var clients []*Pan115Client
+	for _, id := range p.ids {
+		if now.After(p.health[id].until) {
+			clients = append(clients, p.clients[id])
+		}
+	}
+	return clients
+}
+
+// LastError returns the error of the last failure of userID, nil if the account is healthy.
+func (p *AccountPool) LastError(userID int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if h, ok := p.health[userID]; ok {
+		return h.lastErr
+	}
+	return nil
+}
+
+// report records the result of a call to an account.
+func (p *AccountPool) report(userID int64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h, ok := p.health[userID]
+	if !ok {
+		return
+	}
+	if err == nil {
+		*h = accountHealth{}
+		return
+	}
+	h.failures++
+	h.lastErr = err
+	cooldown := p.opts.Cooldown
+	for i := 1; i < h.failures && cooldown < p.opts.MaxCooldown; i++ {
+		cooldown *= 2
+	}
+	if cooldown > p.opts.MaxCooldown {
+		cooldown = p.opts.MaxCooldown
+	}
+	h.until = time.Now().Add(cooldown)
+}
+
+// collect calls fn with every available client concurrently, failed accounts are reported and left out.
+func collect[T any](p *AccountPool, fn func(c *Pan115Client) (T, error)) ([]*Pan115Client, []T) {
+	clients := p.Available()
+	values := make([]T, len(clients))
+	errs := make([]error, len(clients))
+	var wg sync.WaitGroup
+	for i, c := range clients {
+		wg.Add(1)
+		go func(i int, c *Pan115Client) {
+			defer wg.Done()
+			values[i], errs[i] = fn(c)
+			p.report(c.UserID, errs[i])
+		}(i, c)
+	}
+	wg.Wait()
+
+	var (
+		okClients []*Pan115Client
+		okValues  []T
+	)
+	for i, c := range clients {
+		if errs[i] == nil {
+			okClients = append(okClients, c)
+			okValues = append(okValues, values[i])
+		}
+	}
+	return okClients, okValues
+}
+
+// byFreeSpace returns available clients with at least size bytes free, in order of free space descending.
+func (p *AccountPool) byFreeSpace(size int64) []*Pan115Client {
+	clients, remains := collect(p, func(c *Pan115Client) (int64, error) {
+		info, err := c.GetInfo()
+		return info.SpaceInfo.AllRemain.Size, err
+	})
+	idx := make([]int, 0, len(clients))
+	for i := range clients {
+		if remains[i] >= size {
+			idx = append(idx, i)
+		}
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return remains[idx[i]] > remains[idx[j]]
+	})
+	sorted := make([]*Pan115Client, len(idx))
+	for i, j := range idx {
+		sorted[i] = clients[j]
+	}
+	return sorted
+}
+
+// PickForUpload returns the available client with the most free space, which must be at least size bytes.
+func (p *AccountPool) PickForUpload(size int64) (*Pan115Client, error) {
+	clients := p.byFreeSpace(size)
+	if len(clients) == 0 {
+		return nil, ErrNoAvailableAccount
+	}
+	return clients[0], nil
+}
+
+// RapidUploadOrByOSS uploads to the account with the most free space, and falls back to the next one when it fails.
+// dirID returns the target directory in the account, nil means the root directory.
+// The client which the file is uploaded to is returned.
+func (p *AccountPool) RapidUploadOrByOSS(fileName string, fileSize int64, r io.ReadSeeker, dirID func(c *Pan115Client) string) (*Pan115Client, error) {
+	lastErr := ErrNoAvailableAccount
+	for _, c := range p.byFreeSpace(fileSize) {
+		if _, err := r.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		dir := "0"
+		if dirID != nil {
+			dir = dirID(c)
+		}
+		err := c.RapidUploadOrByOSS(dir, fileName, fileSize, r)
+		if errors.Is(err, ErrUploadTooLarge) {
+			// limit of the account, not a failure
+			lastErr = err
+			continue
+		}
+		p.report(c.UserID, err)
+		if err == nil {
+			return c, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// PoolOfflineAddResult is the result of adding an offline task by an URI in an account pool.
+type PoolOfflineAddResult struct {
+	OfflineAddResult
+	// UserID is the account which the task is added to, 0 if there is no quota left in the pool.
+	UserID int64
+}
+
+// AddOfflineTaskURIs spreads offline tasks across available accounts by remaining offline quota,
+// and returns a result per URI in order of uris. A failed account only fails the URIs assigned to it.
+// saveDirID returns the save directory in the account, nil means the default one.
+func (p *AccountPool) AddOfflineTaskURIs(uris []string, saveDirID func(c *Pan115Client) string, opts ...OfflineOption) ([]*PoolOfflineAddResult, error) {
+	if isCalledByAlistV3() {
+		return nil, ErrorNotSupportAlist
+	}
+	clients, quotas := collect(p, func(c *Pan115Client) (*OfflineQuota, error) {
+		return c.GetOfflineQuota()
+	})
+	if len(clients) == 0 {
+		return nil, ErrNoAvailableAccount
+	}
+	remains := make([]int64, len(quotas))
+	for i, quota := range quotas {
+		remains[i] = quota.Remain
+	}
+
+	results := make([]*PoolOfflineAddResult, len(uris))
+	groups := make([][]int, len(clients))
+	for i, k := range spreadByQuota(remains, len(uris)) {
+		results[i] = &PoolOfflineAddResult{OfflineAddResult: OfflineAddResult{URI: uris[i]}}
+		if k < 0 {
+			results[i].Err = ErrOfflineNoTimes
+			continue
+		}
+		groups[k] = append(groups[k], i)
+	}
+
+	var wg sync.WaitGroup
+	for k, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(c *Pan115Client, group []int) {
+			defer wg.Done()
+			groupURIs := make([]string, len(group))
+			for j, i := range group {
+				groupURIs[j] = uris[i]
+			}
+			dir := ""
+			if saveDirID != nil {
+				dir = saveDirID(c)
+			}
+			added, err := c.AddOfflineTaskURIsWithResults(groupURIs, dir, opts...)
+			p.report(c.UserID, err)
+			for j, i := range group {
+				results[i].UserID = c.UserID
+				if err != nil {
+					results[i].Err = err
+					continue
+				}
+				results[i].OfflineAddResult = *added[j]
+			}
+		}(clients[k], group)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// spreadByQuota assigns n tasks one by one to the account with the most remaining quota,
+// returns the account index of each task, -1 if quota runs out.
+func spreadByQuota(remains []int64, n int) []int {
+	left := append([]int64(nil), remains...)
+	assigned := make([]int, n)
+	for i := range assigned {
+		assigned[i] = -1
+		for k := range left {
+			if left[k] > 0 && (assigned[i] < 0 || left[k] > left[assigned[i]]) {
+				assigned[i] = k
+			}
+		}
+		if assigned[i] >= 0 {
+			left[assigned[i]]--
+		}
+	}
+	return assigned
+}
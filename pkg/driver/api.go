@@ -4,9 +4,11 @@ const (
 	ApiGetVersion = "https://appversion.115.com/1/web/1.0/api/chrome"
 
 	// login
-	ApiLoginCheck  = "https://passportapi.115.com/app/1.0/web/1.0/check/sso"
-	ApiUserInfo    = "https://my.115.com/?ct=ajax&ac=nav"
-	ApiStatusCheck = "https://my.115.com/?ct=guide&ac=status"
+	ApiLoginCheck   = "https://passportapi.115.com/app/1.0/web/1.0/check/sso"
+	ApiUserInfo     = "https://my.115.com/?ct=ajax&ac=nav"
+	ApiStatusCheck  = "https://my.115.com/?ct=guide&ac=status"
+	ApiLoginDevices = "https://passportapi.115.com/app/1.0/web/1.0/login_log/login_devices"
+	ApiLogoutDevice = "https://passportapi.115.com/app/1.0/web/1.0/logout/mange"
 	// dir
 	ApiDirAdd = "https://webapi.115.com/files/add"
 	ApiDirName2CID = "https://webapi.115.com/files/getid"
@@ -27,8 +29,28 @@ const (
 	ApiFileStat = "https://webapi.115.com/category/get"
 	ApiFileInfo = "https://webapi.115.com/files/get_info"
 
+	ApiFileDesc   = "https://webapi.115.com/files/desc"
+	ApiFileEdit   = "https://webapi.115.com/files/edit"
+	ApiFileStar   = "https://webapi.115.com/files/star"
+	ApiFileHide   = "https://webapi.115.com/files/hiddenfiles"
+	ApiFileLabel  = "https://webapi.115.com/files/batch_label"
+	ApiFileSearch = "https://webapi.115.com/files/search"
+
+	ApiHiddenSwitch = "https://115.com/?ct=hiddenfiles&ac=switching"
+
+	// label
+	ApiLabelList   = "https://webapi.115.com/label/list"
+	ApiLabelAdd    = "https://webapi.115.com/label/add_multi"
+	ApiLabelEdit   = "https://webapi.115.com/label/edit"
+	ApiLabelDelete = "https://webapi.115.com/label/delete"
+
 	// share
-	ApiShareSnap = "https://webapi.115.com/share/snap"
+	ApiShareSnap    = "https://webapi.115.com/share/snap"
+	ApiShareSend    = "https://webapi.115.com/share/send"
+	ApiShareUpdate  = "https://webapi.115.com/share/updateshare"
+	ApiShareList    = "https://webapi.115.com/share/slist"
+	ApiShareInfo    = "https://webapi.115.com/share/shareinfo"
+	ApiShareReceive = "https://webapi.115.com/share/receive"
 
 	// download
 	ApiDownloadGetUrl        = "https://proapi.115.com/app/chrome/downurl"
@@ -36,10 +58,14 @@ const (
 	AndroidApiDownloadGetUrl = "https://proapi.115.com/android/2.0/ufile/download"
 
 	// offline download
-	ApiAddOfflineUrl   = "https://lixian.115.com/lixianssp/?ac=add_task_urls"
-	ApiDelOfflineUrl   = "https://lixian.115.com/lixian/?ct=lixian&ac=task_del"
-	ApiListOfflineUrl  = "https://lixian.115.com/lixian/?ct=lixian&ac=task_lists"
-	ApiClearOfflineUrl = "https://lixian.115.com/lixian/?ct=lixian&ac=task_clear"
+	ApiAddOfflineUrl      = "https://lixian.115.com/lixianssp/?ac=add_task_urls"
+	ApiAddOfflineBt       = "https://lixian.115.com/lixianssp/?ac=add_task_bt"
+	ApiDelOfflineUrl      = "https://lixian.115.com/lixian/?ct=lixian&ac=task_del"
+	ApiListOfflineUrl     = "https://lixian.115.com/lixian/?ct=lixian&ac=task_lists"
+	ApiClearOfflineUrl    = "https://lixian.115.com/lixian/?ct=lixian&ac=task_clear"
+	ApiOfflineTorrentInfo = "https://lixian.115.com/lixian/?ct=lixian&ac=torrent"
+	ApiOfflineQuota       = "https://lixian.115.com/lixian/?ct=lixian&ac=get_quota_package_info"
+	ApiOfflineSpace       = "https://115.com/?ct=offline&ac=space"
 
 	// upload
 	ApiUploadInfo        = "https://proapi.115.com/app/uploadinfo"
@@ -0,0 +1,150 @@
+package driver
+
+import (
+	"sync"
+)
+
+// BatchFailure describes an id which failed in a batch operation.
+type BatchFailure struct {
+	ID  string
+	Err error
+}
+
+// BatchResult describes the result of a batch operation.
+type BatchResult struct {
+	Succeeded []string
+	Failed    []*BatchFailure
+}
+
+// Ok returns true if no id failed.
+func (r *BatchResult) Ok() bool {
+	return len(r.Failed) == 0
+}
+
+// Err returns the first error of failed ids, or nil.
+func (r *BatchResult) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+	return r.Failed[0].Err
+}
+
+// FailedIDs returns ids which failed.
+func (r *BatchResult) FailedIDs() []string {
+	ids := make([]string, len(r.Failed))
+	for i, f := range r.Failed {
+		ids[i] = f.ID
+	}
+	return ids
+}
+
+// failBatch fails all ids with err.
+func failBatch(ids []string, err error) *BatchResult {
+	result := &BatchResult{}
+	for _, id := range ids {
+		result.Failed = append(result.Failed, &BatchFailure{ID: id, Err: err})
+	}
+	return result
+}
+
+// BatchDelete delete files or directories by chunks
+func (c *Pan115Client) BatchDelete(fileIDs []string, opts ...BatchOption) *BatchResult {
+	if isCalledByAlistV3() {
+		return failBatch(fileIDs, ErrorNotSupportAlist)
+	}
+	return runBatch(fileIDs, func(ids []string) error {
+		return c.Delete(ids...)
+	}, opts...)
+}
+
+// BatchMove move files or directories into another directory by chunks
+func (c *Pan115Client) BatchMove(dirID string, fileIDs []string, opts ...BatchOption) *BatchResult {
+	if isCalledByAlistV3() {
+		return failBatch(fileIDs, ErrorNotSupportAlist)
+	}
+	return runBatch(fileIDs, func(ids []string) error {
+		return c.Move(dirID, ids...)
+	}, opts...)
+}
+
+// BatchCopy copy files or directories into another directory by chunks
+func (c *Pan115Client) BatchCopy(dirID string, fileIDs []string, opts ...BatchOption) *BatchResult {
+	if isCalledByAlistV3() {
+		return failBatch(fileIDs, ErrorNotSupportAlist)
+	}
+	return runBatch(fileIDs, func(ids []string) error {
+		return c.Copy(dirID, ids...)
+	}, opts...)
+}
+
+// runBatch splits ids into chunks and calls fn on every chunk with bounded concurrency.
+func runBatch(ids []string, fn func(ids []string) error, opts ...BatchOption) *BatchResult {
+	o := DefaultBatchOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	result := &BatchResult{}
+	if len(ids) == 0 {
+		return result
+	}
+
+	chunks := splitChunks(ids, o.ChunkSize)
+	results := make([]*BatchResult, len(chunks))
+
+	sem := make(chan struct{}, o.Concurrency)
+	wg := sync.WaitGroup{}
+	for i, chunk := range chunks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, chunk []string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = runChunk(chunk, fn, o.IsolateFailures)
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	// keep the order of input ids
+	for _, r := range results {
+		result.Succeeded = append(result.Succeeded, r.Succeeded...)
+		result.Failed = append(result.Failed, r.Failed...)
+	}
+	return result
+}
+
+func runChunk(chunk []string, fn func(ids []string) error, isolate bool) *BatchResult {
+	result := &BatchResult{}
+	err := fn(chunk)
+	if err == nil {
+		result.Succeeded = append(result.Succeeded, chunk...)
+		return result
+	}
+	if !isolate || len(chunk) == 1 {
+		return failBatch(chunk, err)
+	}
+	for _, id := range chunk {
+		if err := fn([]string{id}); err != nil {
+			result.Failed = append(result.Failed, &BatchFailure{ID: id, Err: err})
+		} else {
+			result.Succeeded = append(result.Succeeded, id)
+		}
+	}
+	return result
+}
+
+func splitChunks(ids []string, size int) [][]string {
+	if size <= 0 {
+		size = len(ids)
+	}
+	chunks := make([][]string, 0, (len(ids)+size-1)/size)
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunks = append(chunks, ids[start:end])
+	}
+	return chunks
+}
@@ -0,0 +1,142 @@
+package driver
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// DecodeBencode decodes bencoded data into int64, string, []any or map[string]any
+func DecodeBencode(data []byte) (any, error) {
+	d := &bencodeDecoder{data: data}
+	v, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, d.error("trailing data")
+	}
+	return v, nil
+}
+
+// bencodeMaxDepth caps nesting of lists and dicts, torrents hardly nest more than a few levels
+const bencodeMaxDepth = 64
+
+type bencodeDecoder struct {
+	data []byte
+	pos  int
+
+	// raw span of the top level "info" value, used by info hash
+	depth     int
+	infoStart int
+	infoEnd   int
+}
+
+func (d *bencodeDecoder) error(msg string) error {
+	return errors.Wrap(ErrBadTorrent, "bencode: "+msg+" at "+strconv.Itoa(d.pos))
+}
+
+func (d *bencodeDecoder) decode() (any, error) {
+	if d.pos >= len(d.data) {
+		return nil, d.error("unexpected end")
+	}
+	switch c := d.data[d.pos]; {
+	case c == 'i':
+		return d.decodeInt()
+	case c == 'l':
+		return d.decodeList()
+	case c == 'd':
+		return d.decodeDict()
+	case c >= '0' && c <= '9':
+		return d.decodeString()
+	default:
+		return nil, d.error("unexpected byte " + strconv.QuoteRune(rune(c)))
+	}
+}
+
+func (d *bencodeDecoder) decodeInt() (int64, error) {
+	end := bytes.IndexByte(d.data[d.pos:], 'e')
+	if end < 0 {
+		return 0, d.error("unterminated int")
+	}
+	i, err := strconv.ParseInt(string(d.data[d.pos+1:d.pos+end]), 10, 64)
+	if err != nil {
+		return 0, d.error("bad int")
+	}
+	d.pos += end + 1
+	return i, nil
+}
+
+func (d *bencodeDecoder) decodeString() (string, error) {
+	colon := bytes.IndexByte(d.data[d.pos:], ':')
+	if colon < 0 {
+		return "", d.error("unterminated string length")
+	}
+	n, err := strconv.Atoi(string(d.data[d.pos : d.pos+colon]))
+	if err != nil || n < 0 {
+		return "", d.error("bad string length")
+	}
+	start := d.pos + colon + 1
+	if n > len(d.data)-start {
+		return "", d.error("string out of range")
+	}
+	d.pos = start + n
+	return string(d.data[start:d.pos]), nil
+}
+
+func (d *bencodeDecoder) decodeList() ([]any, error) {
+	d.pos++
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > bencodeMaxDepth {
+		return nil, d.error("nested too deep")
+	}
+	list := []any{}
+	for {
+		if d.pos >= len(d.data) {
+			return nil, d.error("unterminated list")
+		}
+		if d.data[d.pos] == 'e' {
+			d.pos++
+			return list, nil
+		}
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+}
+
+func (d *bencodeDecoder) decodeDict() (map[string]any, error) {
+	d.pos++
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > bencodeMaxDepth {
+		return nil, d.error("nested too deep")
+	}
+	dict := map[string]any{}
+	for {
+		if d.pos >= len(d.data) {
+			return nil, d.error("unterminated dict")
+		}
+		if d.data[d.pos] == 'e' {
+			d.pos++
+			return dict, nil
+		}
+		key, err := d.decodeString()
+		if err != nil {
+			return nil, err
+		}
+		start := d.pos
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		if d.depth == 1 && key == "info" {
+			d.infoStart, d.infoEnd = start, d.pos
+		}
+		dict[key] = v
+	}
+}
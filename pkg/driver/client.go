@@ -2,6 +2,7 @@ package driver
 
 import (
 	"net/http"
+	"sync"
 
 	"github.com/go-resty/resty/v2"
 )
@@ -14,6 +15,13 @@ type Pan115Client struct {
 	Userkey           string
 	UploadMetaInfo    *UploadMetaInfo
 	UseInternalUpload bool
+
+	credential        *Credential
+	credentialStore   CredentialStore
+	credentialSaveErr error
+	credentialMu      sync.Mutex
+	// requestMu guards Request which is replaced by every NewRequest
+	requestMu sync.Mutex
 }
 
 // New creates Client with customized options.
@@ -65,13 +73,19 @@ func (c *Pan115Client) SetProxy(proxy string) *Pan115Client {
 }
 
 func (c *Pan115Client) NewRequest() *resty.Request {
-	c.Request = c.Client.R()
-	return c.Request
+	req := c.Client.R()
+	c.requestMu.Lock()
+	c.Request = req
+	c.requestMu.Unlock()
+	return req
 }
 
 func (c *Pan115Client) GetRequest() *resty.Request {
-	if c.Request != nil {
-		return c.Request
+	c.requestMu.Lock()
+	req := c.Request
+	c.requestMu.Unlock()
+	if req != nil {
+		return req
 	}
 	return c.NewRequest()
 }
@@ -0,0 +1,280 @@
+package driver
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+// CredentialStore loads and saves credential
+type CredentialStore interface {
+	Load() (*Credential, error)
+	Save(cr *Credential) error
+}
+
+// FileCredentialStore stores credential as JSON in a file with 0600 permission
+type FileCredentialStore struct {
+	Path string
+}
+
+func NewFileCredentialStore(path string) *FileCredentialStore {
+	return &FileCredentialStore{Path: path}
+}
+
+func (s *FileCredentialStore) Load() (*Credential, error) {
+	b, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	cr := &Credential{}
+	if err = json.Unmarshal(b, cr); err != nil {
+		return nil, errors.Wrap(ErrBadCookie, err.Error())
+	}
+	return cr, nil
+}
+
+func (s *FileCredentialStore) Save(cr *Credential) error {
+	b, err := json.MarshalIndent(cr, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.Path, b)
+}
+
+// EnvCredentialStore loads credential from a cookie string in environment variable,
+// Save only changes the environment of current process.
+type EnvCredentialStore struct {
+	Name string
+}
+
+func NewEnvCredentialStore(name string) *EnvCredentialStore {
+	return &EnvCredentialStore{Name: name}
+}
+
+func (s *EnvCredentialStore) Load() (*Credential, error) {
+	cookie, ok := os.LookupEnv(s.Name)
+	if !ok {
+		return nil, errors.Wrap(ErrNotExist, "env "+s.Name)
+	}
+	cr := &Credential{}
+	return cr, cr.FromCookie(cookie)
+}
+
+func (s *EnvCredentialStore) Save(cr *Credential) error {
+	return os.Setenv(s.Name, cr.Cookie())
+}
+
+// EncryptedFileCredentialStore stores credential in a file encrypted by AES-GCM with a key derived from passphrase
+// by scrypt. The file is laid out as magic, salt, nonce and sealed data.
+type EncryptedFileCredentialStore struct {
+	Path       string
+	Passphrase SecretSource
+}
+
+const (
+	encryptedCredentialMagic = "115CRED1"
+	encryptedCredentialSalt  = 16
+)
+
+func NewEncryptedFileCredentialStore(path string, passphrase SecretSource) *EncryptedFileCredentialStore {
+	return &EncryptedFileCredentialStore{Path: path, Passphrase: passphrase}
+}
+
+func (s *EncryptedFileCredentialStore) aead(salt []byte) (cipher.AEAD, error) {
+	passphrase, err := s.Passphrase.Secret()
+	if err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (s *EncryptedFileCredentialStore) Load() (*Credential, error) {
+	b, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(b, []byte(encryptedCredentialMagic)) {
+		return nil, errors.Wrap(ErrBadCookie, "not an encrypted credential file")
+	}
+	b = b[len(encryptedCredentialMagic):]
+	if len(b) < encryptedCredentialSalt {
+		return nil, ErrBadCookie
+	}
+	aead, err := s.aead(b[:encryptedCredentialSalt])
+	if err != nil {
+		return nil, err
+	}
+	b = b[encryptedCredentialSalt:]
+	if len(b) < aead.NonceSize() {
+		return nil, ErrBadCookie
+	}
+	plain, err := aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.Wrap(ErrBadCookie, "decrypt credential")
+	}
+	cr := &Credential{}
+	if err = json.Unmarshal(plain, cr); err != nil {
+		return nil, errors.Wrap(ErrBadCookie, err.Error())
+	}
+	return cr, nil
+}
+
+func (s *EncryptedFileCredentialStore) Save(cr *Credential) error {
+	plain, err := json.Marshal(cr)
+	if err != nil {
+		return err
+	}
+	salt := make([]byte, encryptedCredentialSalt)
+	if _, err = io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+	aead, err := s.aead(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	data := append([]byte(encryptedCredentialMagic), salt...)
+	data = append(data, nonce...)
+	return writeFileAtomic(s.Path, aead.Seal(data, nonce, plain, nil))
+}
+
+// writeFileAtomic writes data into a temp file with 0600 permission then renames it to name
+func writeFileAtomic(name string, data []byte) error {
+	dir := filepath.Dir(name)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, filepath.Base(name)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err = f.Chmod(0o600); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
+
+// UseCredentialStore loads credential from store, and saves credential back into store
+// when cookies are refreshed by Set-Cookie of responses.
+func (c *Pan115Client) UseCredentialStore(store CredentialStore) error {
+	c.credentialMu.Lock()
+	first := c.credentialStore == nil
+	c.credentialStore = store
+	c.credentialMu.Unlock()
+	if first {
+		c.Client.OnAfterResponse(c.saveRefreshedCredential)
+	}
+
+	cr, err := store.Load()
+	if err != nil {
+		return err
+	}
+	c.ImportCredential(cr)
+	return nil
+}
+
+// Credential returns a copy of current credential, nil if no credential is imported
+func (c *Pan115Client) Credential() *Credential {
+	c.credentialMu.Lock()
+	defer c.credentialMu.Unlock()
+	if c.credential == nil {
+		return nil
+	}
+	cr := *c.credential
+	return &cr
+}
+
+func (c *Pan115Client) saveRefreshedCredential(_ *resty.Client, resp *resty.Response) error {
+	if !is115Response(resp) {
+		return nil
+	}
+	cookies := resp.Cookies()
+	if len(cookies) == 0 {
+		return nil
+	}
+	c.credentialMu.Lock()
+	defer c.credentialMu.Unlock()
+	if c.credentialStore == nil || c.credential == nil {
+		return nil
+	}
+	cr := *c.credential
+	for _, cookie := range cookies {
+		if cookie.Value == "" {
+			continue
+		}
+		switch cookie.Name {
+		case CookieNameUid:
+			cr.UID = cookie.Value
+		case CookieNameCid:
+			cr.CID = cookie.Value
+		case CookieNameSeid:
+			cr.SEID = cookie.Value
+		case CookieNameKid:
+			cr.KID = cookie.Value
+		}
+	}
+	if cr == *c.credential {
+		return nil
+	}
+	// the response is good even if saving fails, credential is kept so the next response tries again
+	c.credentialSaveErr = c.credentialStore.Save(&cr)
+	if c.credentialSaveErr == nil {
+		c.credential = &cr
+	}
+	return nil
+}
+
+// LastCredentialSaveError returns the error of the last failed saving of refreshed credential,
+// nil if it is saved or nothing has been refreshed.
+func (c *Pan115Client) LastCredentialSaveError() error {
+	c.credentialMu.Lock()
+	defer c.credentialMu.Unlock()
+	return c.credentialSaveErr
+}
+
+// is115Response tells whether the response comes from 115.com or its subdomains, cookies of other hosts are ignored
+func is115Response(resp *resty.Response) bool {
+	var u *url.URL
+	switch {
+	case resp.RawResponse != nil && resp.RawResponse.Request != nil:
+		// the last request after redirects
+		u = resp.RawResponse.Request.URL
+	case resp.Request != nil && resp.Request.RawRequest != nil:
+		u = resp.Request.RawRequest.URL
+	default:
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == "115.com" || strings.HasSuffix(host, ".115.com")
+}
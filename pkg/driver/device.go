@@ -0,0 +1,67 @@
+package driver
+
+import (
+	"time"
+)
+
+// IsCurrentDevice tells whether it is the device of current client.
+func (d *Device) IsCurrentDevice() bool {
+	return d.IsCurrent == 1
+}
+
+// IsUnusualDevice tells whether the login is marked as unusual.
+func (d *Device) IsUnusualDevice() bool {
+	return d.IsUnusual == 1
+}
+
+// UpdateTime returns the last active time of the device.
+func (d *Device) UpdateTime() time.Time {
+	return time.Unix(int64(d.Utime), 0)
+}
+
+// ListLoginDevices lists the devices which are logged in.
+func (c *Pan115Client) ListLoginDevices() (*LoginDevicesInfo, error) {
+	result := LoginDevicesResp{}
+	req := c.NewRequest().
+		SetResult(&result).
+		ForceContentType("application/json;charset=UTF-8")
+	resp, err := req.Get(ApiLoginDevices)
+	if err = CheckErr(err, &result, resp); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+// KickDevice logs out the device of ssoent, which is the login app slot of the device, e.g. "D1" for ios.
+// Kicking the current device logs out the client itself.
+func (c *Pan115Client) KickDevice(ssoent string) error {
+	if ssoent == "" {
+		return ErrWrongParams
+	}
+	result := LoginDevicesResp{}
+	req := c.NewRequest().
+		SetFormData(map[string]string{"ssoent": ssoent}).
+		SetResult(&result).
+		ForceContentType("application/json;charset=UTF-8")
+	resp, err := req.Post(ApiLogoutDevice)
+	return CheckErr(err, &result, resp)
+}
+
+// KickOtherDevices logs out all devices except the current one, returns devices which are kicked.
+func (c *Pan115Client) KickOtherDevices() ([]Device, error) {
+	info, err := c.ListLoginDevices()
+	if err != nil {
+		return nil, err
+	}
+	var kicked []Device
+	for _, device := range info.List {
+		if device.IsCurrentDevice() {
+			continue
+		}
+		if err := c.KickDevice(device.Ssoent); err != nil {
+			return kicked, err
+		}
+		kicked = append(kicked, device)
+	}
+	return kicked, nil
+}
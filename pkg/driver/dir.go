@@ -50,6 +50,15 @@ func (c *Pan115Client) ListWithLimit(dirID string, limit int64, opts ...ListOpti
 		}
 	}
 
+	var files *[]File
+	err := c.inHiddenMode(o.HiddenPassword, func() (err error) {
+		files, err = c.listWithLimit(dirID, limit, o)
+		return
+	})
+	return files, err
+}
+
+func (c *Pan115Client) listWithLimit(dirID string, limit int64, o *ListOptions) (*[]File, error) {
 	apiURLs := o.ApiURLs
 	var files []File
 	offset := int64(0)
@@ -66,6 +75,9 @@ func (c *Pan115Client) ListWithLimit(dirID string, limit int64, opts ...ListOpti
 			return nil, err
 		}
 		for _, fileInfo := range result.Files {
+			if o.ExcludeHidden && fileInfo.IsHidden != 0 {
+				continue
+			}
 			files = append(files, *(&File{}).from(&fileInfo))
 		}
 		offset = int64(result.Offset) + limit
@@ -73,6 +85,11 @@ func (c *Pan115Client) ListWithLimit(dirID string, limit int64, opts ...ListOpti
 			break
 		}
 	}
+	if o.Description {
+		if err := c.fillDescriptions(files); err != nil {
+			return nil, err
+		}
+	}
 	return &files, nil
 }
 
@@ -85,6 +102,15 @@ func (c *Pan115Client) ListPage(dirID string, offset, limit int64, opts ...ListO
 		}
 	}
 
+	var files *[]File
+	err := c.inHiddenMode(o.HiddenPassword, func() (err error) {
+		files, err = c.listPage(dirID, offset, limit, o)
+		return
+	})
+	return files, err
+}
+
+func (c *Pan115Client) listPage(dirID string, offset, limit int64, o *ListOptions) (*[]File, error) {
 	apiURLs := o.ApiURLs
 	var files []File
 	req := c.NewRequest().ForceContentType("application/json;charset=UTF-8")
@@ -101,8 +127,16 @@ func (c *Pan115Client) ListPage(dirID string, offset, limit int64, opts ...ListO
 		return &files, nil
 	}
 	for _, fileInfo := range result.Files {
+		if o.ExcludeHidden && fileInfo.IsHidden != 0 {
+			continue
+		}
 		files = append(files, *(&File{}).from(&fileInfo))
 	}
+	if o.Description {
+		if err := c.fillDescriptions(files); err != nil {
+			return nil, err
+		}
+	}
 	return &files, nil
 }
 
@@ -1,10 +1,21 @@
 package driver
 
 import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/stretchr/testify/assert"
@@ -435,3 +446,889 @@ func TestGetInfo(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEmpty(t, info.SpaceInfo)
 }
+
+func TestRunBatch(t *testing.T) {
+	ids := []string{"1", "2", "3", "4", "5"}
+	var calls [][]string
+	mu := sync.Mutex{}
+	result := runBatch(ids, func(chunk []string) error {
+		mu.Lock()
+		calls = append(calls, chunk)
+		mu.Unlock()
+		for _, id := range chunk {
+			if id == "4" {
+				return ErrNotExist
+			}
+		}
+		return nil
+	}, BatchWithChunkSize(2), BatchWithConcurrency(1))
+
+	assert.Equal(t, []string{"1", "2", "3", "5"}, result.Succeeded)
+	assert.Equal(t, []string{"4"}, result.FailedIDs())
+	assert.ErrorIs(t, result.Err(), ErrNotExist)
+	// 3 chunks, and the failed chunk is retried one by one
+	assert.Len(t, calls, 5)
+
+	result = runBatch(ids, func(chunk []string) error {
+		return ErrNotExist
+	}, BatchWithChunkSize(2), BatchWithIsolateFailures(false))
+	assert.Empty(t, result.Succeeded)
+	assert.Len(t, result.Failed, 5)
+}
+
+func TestPreviewRename(t *testing.T) {
+	files := []File{
+		{FileID: "1", Name: "[Sub] Show - 01 [1080p].MKV"},
+		{FileID: "2", Name: "[Sub] Show - 02 [1080p].MKV"},
+		{FileID: "3", Name: "show.s01e03.mkv"},
+	}
+	previews := PreviewRename(files,
+		RenameRegexp(regexp.MustCompile(`^\[Sub\] Show - \d+ \[1080p\]`), "Show"),
+		RenameToCase(RenameCaseLower),
+		RenameTemplate("{name} E{n}{ext}", 1, 2),
+	)
+	assert.Equal(t, []RenamePreview{
+		{FileID: "1", OldName: files[0].Name, NewName: "show E01.mkv"},
+		{FileID: "2", OldName: files[1].Name, NewName: "show E02.mkv"},
+		{FileID: "3", OldName: files[2].Name, NewName: "show.s01e03 E03.mkv"},
+	}, previews)
+
+	previews = PreviewRename(files, RenameExtension("mp4"))
+	assert.Equal(t, "[Sub] Show - 01 [1080p].mp4", previews[0].NewName)
+	previews = PreviewRename(files[2:], RenameExtension(".mkv"))
+	assert.Empty(t, previews)
+	assert.Equal(t, "Hello  World.txt", RenameToCase(RenameCaseTitle)(0, "hello  WORLD.txt"))
+	assert.Equal(t, " Hello\tWorld.txt", RenameToCase(RenameCaseTitle)(0, " hello\tworld.txt"))
+	assert.Equal(t, "中文 Épisode.mkv", RenameToCase(RenameCaseTitle)(0, "中文 épisode.mkv"))
+}
+
+func TestLabelColor(t *testing.T) {
+	assert.Equal(t, "#2670FC", LabelColor(5).Hex())
+	assert.Equal(t, "#000000", LabelColor(99).Hex())
+	l := (&Label{}).from(&LabelInfo{ID: "1", Name: "a", Color: "#43ba80"})
+	assert.Equal(t, LabelColor(4), l.Color)
+}
+
+func TestLabels(t *testing.T) {
+	down := teardown(t)
+	defer down(t)
+
+	label, err := client.CreateLabel(NowMilli().String(), LabelColor(1))
+	assert.Nil(t, err)
+	assert.Nil(t, client.RenameLabel(label.ID, NowMilli().String()))
+	assert.Nil(t, client.RecolorLabel(label.ID, LabelColor(2)))
+	labels, err := client.ListLabels()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, labels)
+	_, err = client.ListByLabel(label.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, client.DeleteLabels(label.ID))
+}
+
+func TestDescription(t *testing.T) {
+	down := teardown(t)
+	defer down(t)
+
+	cid, err := client.Mkdir("0", NowMilli().String())
+	assert.Nil(t, err)
+	defer client.Delete(cid)
+	assert.Nil(t, client.SetDescription(cid, "source: https://example.com"))
+	desc, err := client.GetDescription(cid)
+	assert.Nil(t, err)
+	assert.Contains(t, desc, "example.com")
+	f, err := client.GetFile(cid, WithDescription())
+	assert.Nil(t, err)
+	assert.Equal(t, desc, f.Description)
+}
+
+func TestHiddenMode(t *testing.T) {
+	down := teardown(t)
+	defer down(t)
+
+	assert.Error(t, client.EnterHiddenMode("wrong password"))
+	assert.Nil(t, client.ExitHiddenMode())
+}
+
+func TestListIncludeHidden(t *testing.T) {
+	var calls []string
+	c := New()
+	c.Client.SetTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		w := httptest.NewRecorder()
+		if r.URL.Query().Get("ct") == "hiddenfiles" {
+			assert.Nil(t, r.ParseForm())
+			calls = append(calls, "show="+r.PostForm.Get("show"))
+			_, _ = w.WriteString(`{"state":true}`)
+		} else {
+			calls = append(calls, "list")
+			_, _ = w.WriteString(`{"state":true,"cid":"0","count":1,"data":[{"fid":"1","n":"secret.txt","hdf":1}]}`)
+		}
+		resp := w.Result()
+		resp.Request = r
+		return resp, nil
+	}))
+	files, err := c.List("0", WithIncludeHidden("1234"))
+	assert.Nil(t, err)
+	assert.Len(t, *files, 1)
+	assert.True(t, (*files)[0].Hidden)
+	assert.Equal(t, []string{"show=1", "list", "show=0"}, calls)
+}
+
+func TestShare(t *testing.T) {
+	down := teardown(t)
+	defer down(t)
+
+	cid, err := client.Mkdir("0", NowMilli().String())
+	assert.Nil(t, err)
+	defer client.Delete(cid)
+	share, err := client.CreateShare([]string{cid}, ShareWithDuration(1), ShareWithReceiveCode("abcd"))
+	assert.Nil(t, err)
+	assert.Equal(t, "abcd", share.ReceiveCode)
+	shares, err := client.ListShares()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, shares)
+	assert.Nil(t, client.CancelShares(share.ShareCode))
+}
+
+func TestReceiveShare(t *testing.T) {
+	down := teardown(t)
+	defer down(t)
+
+	_, err := client.ReceiveShare("sw6pw793wfp", "w816", nil, "0")
+	assert.ErrorIs(t, err, ErrSharedNotFound)
+}
+
+func TestParseShareURL(t *testing.T) {
+	for link, codes := range map[string][2]string{
+		"https://115.com/s/sw6pw793wfp?password=w816":           {"sw6pw793wfp", "w816"},
+		"https://115cdn.com/s/sw6pw793wfp?password=w816&#":      {"sw6pw793wfp", "w816"},
+		"链接：https://115.com/s/sw6pw793wfp 访问码：w816 复制这段内容打开115": {"sw6pw793wfp", "w816"},
+		"https://115.com/s/sw6pw793wfp":                         {"sw6pw793wfp", ""},
+	} {
+		shareCode, receiveCode, err := ParseShareURL(link)
+		assert.Nil(t, err, link)
+		assert.Equal(t, codes[0], shareCode, link)
+		assert.Equal(t, codes[1], receiveCode, link)
+	}
+	_, _, err := ParseShareURL("https://115.com/")
+	assert.ErrorIs(t, err, ErrSharedInvalid)
+	_, _, err = ParseShareURL("sw6pw793wfp")
+	assert.ErrorIs(t, err, ErrSharedInvalid)
+}
+
+func TestWalkShare(t *testing.T) {
+	down := teardown(t)
+	defer down(t)
+
+	err := client.WalkShare(context.Background(), "https://115.com/s/sw6pw793wfp?password=w816", func(filePath string, file *ShareFile) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrSharedNotFound)
+}
+
+// stubShare serves share snaps by cid from lists, and downloads of shared files by serve,
+// the download url of a shared file is https://cdn.example.com/<fid>
+func stubShare(t *testing.T, lists map[string][]ShareFile, serve http.HandlerFunc) *Pan115Client {
+	c := New()
+	c.Client.SetTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		w := httptest.NewRecorder()
+		if strings.HasPrefix(r.URL.String(), ApiShareSnap) {
+			list := lists[r.URL.Query().Get("cid")]
+			data, err := json.Marshal(list)
+			assert.Nil(t, err)
+			_, _ = fmt.Fprintf(w, `{"state":true,"data":{"count":%d,"list":%s}}`, len(list), data)
+		} else {
+			serve(w, r)
+		}
+		resp := w.Result()
+		resp.Request = r
+		return resp, nil
+	}))
+	orig := shareDownloadURL
+	shareDownloadURL = func(_ *Pan115Client, _, _, fileID string) (string, error) {
+		return "https://cdn.example.com/" + fileID, nil
+	}
+	t.Cleanup(func() { shareDownloadURL = orig })
+	return c
+}
+
+func TestDownloadShare(t *testing.T) {
+	content := "content of a shared file"
+	hash := sha1.Sum([]byte(content))
+	lists := map[string][]ShareFile{
+		"":   {{CategoryID: "10", FileName: "dir"}},
+		"10": {{FileID: "11", CategoryID: "10", FileName: "a.txt", IsFile: 1, Size: StringInt64(len(content)), Sha1: hex.EncodeToString(hash[:])}},
+	}
+	// download returns content of the target and whether the part file is left
+	download := func(t *testing.T, part, existing string, serve http.HandlerFunc) (string, bool, error) {
+		dir, err := os.MkdirTemp("./", "test-share-*")
+		assert.Nil(t, err)
+		t.Cleanup(func() { os.RemoveAll(dir) })
+		target := filepath.Join(dir, "dir", "a.txt")
+		assert.Nil(t, os.MkdirAll(filepath.Dir(target), 0o755))
+		if part != "" {
+			assert.Nil(t, os.WriteFile(target+".part", []byte(part), 0o644))
+		}
+		if existing != "" {
+			assert.Nil(t, os.WriteFile(target, []byte(existing), 0o644))
+		}
+		err = stubShare(t, lists, serve).DownloadShare(context.Background(), "share", "code", dir)
+		_, statErr := os.Stat(target + ".part")
+		data, _ := os.ReadFile(target)
+		return string(data), statErr == nil, err
+	}
+
+	t.Run("resume", func(t *testing.T) {
+		data, partLeft, err := download(t, content[:7], "", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "https://cdn.example.com/11", r.URL.String())
+			assert.Equal(t, "bytes=7-", r.Header.Get("Range"))
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte(content[7:]))
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, content, data)
+		assert.False(t, partLeft)
+	})
+	t.Run("restart when range is ignored", func(t *testing.T) {
+		data, partLeft, err := download(t, "garbage", "", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(content))
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, content, data)
+		assert.False(t, partLeft)
+	})
+	t.Run("sha1 mismatch", func(t *testing.T) {
+		data, partLeft, err := download(t, "", "", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(strings.ToUpper(content)))
+		})
+		assert.ErrorIs(t, err, ErrDownloadSha1Mismatch)
+		assert.Empty(t, data)
+		assert.False(t, partLeft, "bad part file is removed")
+	})
+	t.Run("keep part on bad status", func(t *testing.T) {
+		_, partLeft, err := download(t, content[:7], "", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+		assert.ErrorIs(t, err, ErrUnexpected)
+		assert.True(t, partLeft)
+	})
+	t.Run("skip verified file", func(t *testing.T) {
+		data, _, err := download(t, "", content, func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected download of %s", r.URL)
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, content, data)
+	})
+}
+
+func TestFromShareFile(t *testing.T) {
+	dir := (&File{}).FromShareFile(&ShareFile{CategoryID: "10", ParentID: "1", FileName: "dir", UpdateTime: "1700000000"})
+	assert.True(t, dir.IsDirectory)
+	assert.Equal(t, "10", dir.FileID)
+	assert.Equal(t, "1", dir.ParentID)
+	assert.Equal(t, int64(1700000000), dir.UpdateTime.Unix())
+
+	f := (&File{}).FromShareFile(&ShareFile{
+		FileID: "11", CategoryID: "10", FileName: "a.mp4", IsFile: 1, Size: 1024, UpdateTime: "2023-11-15 06:13",
+		Labels: []*LabelInfo{{ID: "1", Name: "l", Color: "#FF4B30"}},
+	})
+	assert.False(t, f.IsDirectory)
+	assert.Equal(t, "11", f.FileID)
+	assert.Equal(t, "10", f.ParentID)
+	assert.Equal(t, int64(1024), f.Size)
+	assert.Equal(t, int64(1700000000-20), f.UpdateTime.Unix())
+	assert.Equal(t, LabelColor(1), f.Labels[0].Color)
+}
+
+func TestShareFS(t *testing.T) {
+	contents := map[string]string{"11": "episode one", "20": "readme"}
+	lists := map[string][]ShareFile{
+		"": {
+			{CategoryID: "10", FileName: "show", UpdateTime: "1700000000"},
+			{FileID: "20", FileName: "readme.txt", IsFile: 1, Size: StringInt64(len(contents["20"])), UpdateTime: "1700000000"},
+		},
+		"10": {
+			{CategoryID: "12", ParentID: "10", FileName: "extras", UpdateTime: "1700000000"},
+			{FileID: "11", CategoryID: "10", FileName: "e01.mkv", IsFile: 1, Size: StringInt64(len(contents["11"])), UpdateTime: "1700000000"},
+		},
+	}
+	fsys := stubShare(t, lists, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(contents[strings.TrimPrefix(r.URL.Path, "/")]))
+	}).ShareFS("share", "code")
+	assert.Nil(t, fstest.TestFS(fsys, "readme.txt", "show/e01.mkv", "show/extras"))
+}
+
+func TestOfflineTaskFilter(t *testing.T) {
+	tasks := []*OfflineTask{
+		{Name: "Show.S01E01.mkv", Status: OfflineTaskStatusDone, DirId: "1"},
+		{Name: "Show.S01E02.mkv", Status: OfflineTaskStatusRunning, DirId: "1"},
+		{Name: "movie.mp4", Status: OfflineTaskStatusFailed, DirId: "2"},
+	}
+	var matched []*OfflineTask
+	filters := []OfflineTaskFilter{
+		OfflineTaskWithStatus(OfflineTaskStatusDone, OfflineTaskStatusFailed),
+		OfflineTaskNameContains("show"),
+		OfflineTaskInDir("1"),
+	}
+	for _, task := range tasks {
+		if matchOfflineTask(task, filters) {
+			matched = append(matched, task)
+		}
+	}
+	assert.Equal(t, tasks[:1], matched)
+}
+
+func TestListAllOfflineTasks(t *testing.T) {
+	down := teardown(t)
+	defer down(t)
+
+	_, err := client.ListAllOfflineTasks(OfflineTaskWithStatus(OfflineTaskStatusDone))
+	assert.Nil(t, err)
+	_, err = client.GetOfflineTaskByHash(NowMilli().String())
+	assert.ErrorIs(t, err, ErrNotExist)
+}
+
+func TestDiffOfflineTasks(t *testing.T) {
+	prev := offlineTaskMap([]*OfflineTask{
+		{InfoHash: "a", Status: OfflineTaskStatusRunning, Percent: 10},
+		{InfoHash: "b", Status: OfflineTaskStatusRunning, Percent: 10},
+		{InfoHash: "c", Status: OfflineTaskStatusRunning, Percent: 10},
+		{InfoHash: "d", Status: OfflineTaskStatusDone, Percent: 100},
+	})
+	curr := offlineTaskMap([]*OfflineTask{
+		{InfoHash: "a", Status: OfflineTaskStatusRunning, Percent: 20},
+		{InfoHash: "b", Status: OfflineTaskStatusDone, Percent: 100},
+		{InfoHash: "d", Status: OfflineTaskStatusDone, Percent: 100},
+		{InfoHash: "E", Status: OfflineTaskStatusTodo},
+	})
+	missing := map[string]bool{}
+	diff := func(prev, curr map[string]*OfflineTask) map[string][]OfflineTaskEventType {
+		events := map[string][]OfflineTaskEventType{}
+		for _, e := range diffOfflineTasks(prev, curr, missing) {
+			events[e.Task.InfoHash] = append(events[e.Task.InfoHash], e.Type)
+		}
+		return events
+	}
+	assert.Equal(t, map[string][]OfflineTaskEventType{
+		"a": {OfflineTaskEventProgress},
+		"b": {OfflineTaskEventDone},
+		"E": {OfflineTaskEventAdded},
+	}, diff(prev, curr))
+
+	// c is removed after missing twice, d flaps back without events
+	next := offlineTaskMap([]*OfflineTask{
+		{InfoHash: "a", Status: OfflineTaskStatusRunning, Percent: 20},
+		{InfoHash: "b", Status: OfflineTaskStatusDone, Percent: 100},
+		{InfoHash: "E", Status: OfflineTaskStatusTodo},
+	})
+	assert.Equal(t, map[string][]OfflineTaskEventType{
+		"c": {OfflineTaskEventRemoved},
+	}, diff(curr, next))
+	last := offlineTaskMap([]*OfflineTask{
+		{InfoHash: "a", Status: OfflineTaskStatusRunning, Percent: 20},
+		{InfoHash: "b", Status: OfflineTaskStatusDone, Percent: 100},
+		{InfoHash: "d", Status: OfflineTaskStatusDone, Percent: 100},
+		{InfoHash: "E", Status: OfflineTaskStatusTodo},
+	})
+	assert.Empty(t, diff(next, last))
+	assert.Empty(t, missing)
+}
+
+func TestDecodeBencode(t *testing.T) {
+	v, err := DecodeBencode([]byte("d3:cow3:moo4:spaml1:a1:be1:ii-3ee"))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{"cow": "moo", "spam": []any{"a", "b"}, "i": int64(-3)}, v)
+
+	deep := strings.Repeat("l", 10000)
+	for _, bad := range []string{"", "i12", "5:abc", "l1:a", "d1:ai1e", "x", "i1ei2e", "9223372036854775807:abc", deep} {
+		_, err := DecodeBencode([]byte(bad))
+		assert.ErrorIs(t, err, ErrBadTorrent, bad)
+	}
+}
+
+func TestParseTorrent(t *testing.T) {
+	info := "d5:filesld6:lengthi100e4:pathl3:dir5:a.mkveed6:lengthi20e4:pathl5:b.txteee4:name4:show12:piece lengthi16384ee"
+	data := "d8:announce3:url4:info" + info + "e"
+	torrent, err := ParseTorrent(strings.NewReader(data))
+	assert.Nil(t, err)
+	hash := sha1.Sum([]byte(info))
+	assert.Equal(t, hex.EncodeToString(hash[:]), torrent.InfoHash)
+	assert.Equal(t, "show", torrent.Name)
+	assert.Equal(t, int64(120), torrent.Size)
+	assert.Equal(t, []TorrentFile{
+		{Index: 0, Path: "dir/a.mkv", Size: 100},
+		{Index: 1, Path: "b.txt", Size: 20},
+	}, torrent.Files)
+
+	torrent, err = ParseTorrent(strings.NewReader("d4:infod6:lengthi7e4:name5:a.isoee"))
+	assert.Nil(t, err)
+	assert.Equal(t, []TorrentFile{{Path: "a.iso", Size: 7}}, torrent.Files)
+
+	_, err = ParseTorrent(strings.NewReader("d3:fooi1ee"))
+	assert.ErrorIs(t, err, ErrBadTorrent)
+}
+
+func TestValidateOfflineURI(t *testing.T) {
+	for _, uri := range []string{
+		"magnet:?xt=urn:btih:c9e15763f722f23e98a29decdfae341b98d53056&dn=test",
+		"magnet:?xt=urn:btih:ZHQVOY7XELZD5GFCTXW57LRUDOMNKMCW",
+		"ed2k://|file|a.mkv|1024|0123456789abcdef0123456789ABCDEF|/",
+		"https://example.com/a.iso",
+		"ftp://example.com/a.iso",
+	} {
+		assert.Nil(t, ValidateOfflineURI(uri), uri)
+	}
+	for _, uri := range []string{
+		"magnet:?xt=urn:btih:123",
+		"magnet:?dn=test",
+		"ed2k://|file|a.mkv|abc|0123|/",
+		"https://",
+		"thunder://abc",
+		"",
+	} {
+		assert.ErrorIs(t, ValidateOfflineURI(uri), ErrOfflineInvalidLink, uri)
+	}
+}
+
+func TestGetOfflineQuota(t *testing.T) {
+	down := teardown(t)
+	defer down(t)
+
+	quota, err := client.GetOfflineQuota()
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, quota.Total, quota.Remain)
+}
+
+func TestOfflineTaskResponseErr(t *testing.T) {
+	assert.Nil(t, (&OfflineTaskResponse{State: true, InfoHash: "a"}).Err())
+	assert.ErrorIs(t, (&OfflineTaskResponse{ErrCode: 10008, InfoHash: "a"}).Err(), ErrOfflineTaskExisted)
+	assert.ErrorIs(t, (&OfflineTaskResponse{ErrCode: 10004}).Err(), ErrOfflineInvalidLink)
+}
+
+func TestMatchOfflineAddResults(t *testing.T) {
+	uris := []string{"magnet:?xt=urn:btih:a", "magnet:?xt=urn:btih:b"}
+
+	_, err := matchOfflineAddResults(uris, &OfflineAddUrlResponse{BasicResp: BasicResp{ErrNo: 10010}})
+	assert.ErrorIs(t, err, ErrOfflineNoTimes)
+
+	results, err := matchOfflineAddResults(uris, &OfflineAddUrlResponse{
+		BasicResp: BasicResp{State: true},
+		Result: []OfflineTaskResponse{
+			{Url: uris[1], ErrCode: 10008, InfoHash: "b"},
+			{Url: uris[0], State: true, InfoHash: "a"},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "a", results[0].InfoHash)
+	assert.Nil(t, results[0].Err)
+	assert.ErrorIs(t, results[1].Err, ErrOfflineTaskExisted)
+}
+
+func TestOfflineAddUriWithResults(t *testing.T) {
+	down := teardown(t)
+	defer down(t)
+
+	uris := []string{"https://x.com/Olympics/status/1820550228640203065/photo/1", "magnet:?xt=urn:btih:123"}
+	results, err := client.AddOfflineTaskURIsWithResults(uris, "0", WithPreflight())
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, uris[1], results[1].URI)
+	assert.ErrorIs(t, results[1].Err, ErrOfflineInvalidLink)
+}
+
+func TestOfflinePostRuleJunk(t *testing.T) {
+	rule := &OfflinePostRule{JunkExts: []string{".txt", ".URL"}, SampleSize: 50 * MB}
+	assert.True(t, rule.isJunk(&File{Name: "readme.TXT"}))
+	assert.True(t, rule.isJunk(&File{Name: "site.url"}))
+	assert.True(t, rule.isJunk(&File{Name: "Show-Sample.mkv", Size: 10 * MB}))
+	assert.False(t, rule.isJunk(&File{Name: "Show-Sample.mkv", Size: 100 * MB}))
+	assert.False(t, rule.isJunk(&File{Name: "Show.mkv", Size: 10 * MB}))
+	assert.False(t, rule.isJunk(&File{Name: "sample.txt", IsDirectory: true}))
+}
+
+func TestRecycleBinFilter(t *testing.T) {
+	now := time.Now()
+	items := []RecycleBinItem{
+		{FileName: "Show.mkv", ParentId: "1", DeleteTime: StringInt64(now.Add(-time.Hour).Unix())},
+		{FileName: "show.txt", ParentId: "2", DeleteTime: StringInt64(now.Add(-time.Hour).Unix())},
+		{FileName: "Show.nfo", ParentId: "1", DeleteTime: StringInt64(now.Add(-48 * time.Hour).Unix())},
+	}
+	filters := []RecycleBinFilter{
+		RecycleBinNameContains("SHOW"),
+		RecycleBinInDir("1"),
+		RecycleBinDeletedBetween(now.Add(-24*time.Hour), time.Time{}),
+	}
+	var matched []RecycleBinItem
+	for i := range items {
+		if matchRecycleBinItem(&items[i], filters) {
+			matched = append(matched, items[i])
+		}
+	}
+	assert.Equal(t, items[:1], matched)
+}
+
+func TestRecycleBinItemsStopByCount(t *testing.T) {
+	c := New()
+	c.Client.SetTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		// pages are capped at 2 items, an offset out of range is clamped to the last page
+		body := `{"state":true,"count":"3","data":[{"id":"a"},{"id":"b"}]}`
+		if r.URL.Query().Get("offset") != "0" {
+			body = `{"state":true,"count":"3","data":[{"id":"c"}]}`
+		}
+		w := httptest.NewRecorder()
+		_, _ = w.WriteString(body)
+		resp := w.Result()
+		resp.Request = r
+		return resp, nil
+	}))
+	items, err := c.ListAllRecycleBin()
+	assert.Nil(t, err)
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.FileId)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+}
+
+func TestListAllRecycleBin(t *testing.T) {
+	down := teardown(t)
+	defer down(t)
+
+	_, err := client.ListAllRecycleBin(RecycleBinDeletedBetween(time.Now().AddDate(0, 0, -7), time.Time{}))
+	assert.Nil(t, err)
+}
+
+func TestRecycleRetentionReason(t *testing.T) {
+	now := time.Now()
+	policy := &RecycleRetentionPolicy{MaxAge: 30 * 24 * time.Hour, MaxSize: GB}
+	old := &RecycleBinItem{DeleteTime: StringInt64(now.AddDate(0, 0, -31).Unix())}
+	big := &RecycleBinItem{DeleteTime: StringInt64(now.Unix()), FileSize: 2 * GB}
+	keep := &RecycleBinItem{DeleteTime: StringInt64(now.Unix()), FileSize: MB}
+	assert.Equal(t, RetentionReasonAge, policy.reason(old, now))
+	assert.Equal(t, RetentionReasonSize, policy.reason(big, now))
+	assert.Equal(t, "", policy.reason(keep, now))
+}
+
+func TestSecretSource(t *testing.T) {
+	t.Setenv("TEST_115_SECRET", "123456")
+	secret, err := EnvSecret("TEST_115_SECRET").Secret()
+	assert.Nil(t, err)
+	assert.Equal(t, "123456", secret)
+	_, err = EnvSecret("TEST_115_SECRET_NOT_SET").Secret()
+	assert.ErrorIs(t, err, ErrNotExist)
+
+	f, err := os.CreateTemp("./", "test-secret-*")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+	_, _ = f.WriteString("654321\n")
+	f.Close()
+	secret, err = FileSecret(f.Name()).Secret()
+	assert.Nil(t, err)
+	assert.Equal(t, "654321", secret)
+}
+
+func TestApplyRecycleRetention(t *testing.T) {
+	down := teardown(t)
+	defer down(t)
+
+	buf := &strings.Builder{}
+	_, err := client.ApplyRecycleRetention(&RecycleRetentionPolicy{MaxAge: 24 * time.Hour, DryRun: true, Log: buf})
+	assert.Nil(t, err)
+}
+
+func TestCredentialStore(t *testing.T) {
+	dir, err := os.MkdirTemp("./", "test-credential-*")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	cr := &Credential{UID: "1", CID: "2", SEID: "3", KID: "4"}
+
+	fileStore := NewFileCredentialStore(filepath.Join(dir, "sub", "credential.json"))
+	assert.Nil(t, fileStore.Save(cr))
+	stat, err := os.Stat(fileStore.Path)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0o600), stat.Mode().Perm())
+	loaded, err := fileStore.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, cr, loaded)
+
+	t.Setenv("TEST_115_PASSPHRASE", "passphrase")
+	encStore := NewEncryptedFileCredentialStore(filepath.Join(dir, "credential.enc"), EnvSecret("TEST_115_PASSPHRASE"))
+	assert.Nil(t, encStore.Save(cr))
+	loaded, err = encStore.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, cr, loaded)
+	t.Setenv("TEST_115_PASSPHRASE", "wrong")
+	_, err = encStore.Load()
+	assert.ErrorIs(t, err, ErrBadCookie)
+
+	t.Setenv("TEST_115_COOKIE", cr.Cookie())
+	loaded, err = NewEnvCredentialStore("TEST_115_COOKIE").Load()
+	assert.Nil(t, err)
+	assert.Equal(t, cr, loaded)
+}
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestCredentialRefresh(t *testing.T) {
+	dir, err := os.MkdirTemp("./", "test-credential-*")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewFileCredentialStore(filepath.Join(dir, "credential.json"))
+	assert.Nil(t, store.Save(&Credential{UID: "1", CID: "2", SEID: "3", KID: "4"}))
+	c := New()
+	assert.Nil(t, c.UseCredentialStore(store))
+	assert.Equal(t, "3", c.Credential().SEID)
+	c.Client.SetTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		w := httptest.NewRecorder()
+		http.SetCookie(w, &http.Cookie{Name: CookieNameSeid, Value: "refreshed-by-" + r.URL.Hostname()})
+		resp := w.Result()
+		resp.Request = r
+		return resp, nil
+	}))
+
+	// cookies of other hosts are ignored
+	_, err = c.NewRequest().Get("https://cdn.example.com/file")
+	assert.Nil(t, err)
+	loaded, err := store.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, "3", loaded.SEID)
+
+	_, err = c.NewRequest().Get("https://webapi.115.com/files")
+	assert.Nil(t, err)
+	loaded, err = store.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, "refreshed-by-webapi.115.com", loaded.SEID)
+	assert.Equal(t, "refreshed-by-webapi.115.com", c.Credential().SEID)
+	assert.Nil(t, c.LastCredentialSaveError())
+
+	// credential is kept until saving succeeds
+	assert.Nil(t, os.RemoveAll(dir))
+	assert.Nil(t, os.WriteFile(dir, nil, 0o600))
+	_, err = c.NewRequest().Get("https://proapi.115.com/files")
+	assert.Nil(t, err)
+	assert.Error(t, c.LastCredentialSaveError())
+	assert.Equal(t, "refreshed-by-webapi.115.com", c.Credential().SEID)
+}
+
+func TestQRCodeTerminal(t *testing.T) {
+	s := &QRCodeSession{QrcodeContent: "https://115.com/scan/dg-test"}
+	big, err := s.QRCodeTerminal(false, false)
+	assert.Nil(t, err)
+	small, err := s.QRCodeTerminal(true, false)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, small)
+	assert.Less(t, strings.Count(small, "\n"), strings.Count(big, "\n"))
+}
+
+func TestLoginByQRCode(t *testing.T) {
+	_, err := New().LoginByQRCode(context.Background(), LoginAppTV, nil)
+	assert.ErrorIs(t, err, ErrWrongParams)
+
+	if os.Getenv("QRCODE_LOGIN") == "" {
+		t.Skip("scanning a qrcode is interactive, set QRCODE_LOGIN to run")
+	}
+	c := New()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	cr, err := c.LoginByQRCode(ctx, LoginAppTV, func(s *QRCodeSession) {
+		code, _ := s.QRCodeTerminal(true, false)
+		t.Log("\n" + code)
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, cr.UID)
+}
+
+func TestSessionHealth(t *testing.T) {
+	h := &sessionHealth{threshold: 2}
+	assert.Nil(t, h.update(true, nil))
+	assert.Nil(t, h.update(false, nil))
+	e := h.update(false, io.ErrUnexpectedEOF)
+	assert.Equal(t, SessionEventError, e.Type)
+	e = h.update(false, nil)
+	assert.Equal(t, SessionEventInvalid, e.Type)
+	assert.ErrorIs(t, e.Err, ErrBadCookie)
+	assert.Nil(t, h.update(false, nil))
+	e = h.update(true, nil)
+	assert.Equal(t, SessionEventRecovered, e.Type)
+	assert.Nil(t, h.update(true, nil))
+}
+
+func TestMonitorSession(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	invalid := make(chan error, 1)
+	c := New().ImportCredential(&Credential{})
+	events := c.MonitorSession(ctx,
+		SessionMonitorWithInterval(time.Millisecond*10),
+		SessionMonitorWithFailureThreshold(1),
+		SessionMonitorWithOnInvalid(func(err error) { invalid <- err }),
+	)
+	select {
+	case e := <-events:
+		if e.Type == SessionEventInvalid {
+			assert.ErrorIs(t, <-invalid, ErrBadCookie)
+		}
+	case <-time.After(time.Second * 30):
+		t.Error("no session event")
+	}
+	cancel()
+	for range events {
+	}
+
+	// a zero interval falls back to the default instead of panicking
+	for range c.MonitorSession(ctx, SessionMonitorWithInterval(0)) {
+	}
+}
+
+func TestSpreadByQuota(t *testing.T) {
+	assert.Equal(t, []int{1, 0, 1, 0, 1, -1}, spreadByQuota([]int64{2, 3, 0}, 6))
+	assert.Equal(t, []int{-1}, spreadByQuota(nil, 1))
+}
+
+func TestAccountPool(t *testing.T) {
+	pool := NewAccountPool(AccountPoolWithCooldown(time.Hour, time.Hour*2))
+	a, b := New(), New()
+	a.UserID, b.UserID = 1, 2
+	assert.Nil(t, pool.Add(a))
+	assert.Nil(t, pool.Add(b))
+	assert.Len(t, pool.Clients(), 2)
+
+	pool.report(1, ErrNotLogin)
+	assert.Equal(t, []*Pan115Client{b}, pool.Available())
+	assert.ErrorIs(t, pool.LastError(1), ErrNotLogin)
+	pool.report(1, nil)
+	assert.Len(t, pool.Available(), 2)
+	assert.Nil(t, pool.LastError(1))
+
+	pool.Remove(1)
+	_, ok := pool.Get(1)
+	assert.False(t, ok)
+	assert.Equal(t, []*Pan115Client{b}, pool.Clients())
+}
+
+func TestLoginDevices(t *testing.T) {
+	down := teardown(t)
+	defer down(t)
+	info, err := client.ListLoginDevices()
+	assert.Nil(t, err)
+	current := 0
+	for _, device := range info.List {
+		if device.IsCurrentDevice() {
+			current++
+		}
+	}
+	assert.Equal(t, 1, current)
+	assert.ErrorIs(t, client.KickDevice(""), ErrWrongParams)
+}
+
+func TestPickLoginApp(t *testing.T) {
+	app, ok := LoginAppBySsoent("I1")
+	assert.True(t, ok)
+	assert.Equal(t, LoginAppTV, app)
+
+	info := &LoginDevicesInfo{List: []Device{{Ssoent: "I1"}, {Ssoent: "R2"}}}
+	app, err := PickLoginApp(info)
+	assert.Nil(t, err)
+	assert.Equal(t, LoginAppWechatMini, app)
+	app, err = PickLoginApp(info, LoginAppTV, LoginAppIOS)
+	assert.Nil(t, err)
+	assert.Equal(t, LoginAppIOS, app)
+	_, err = PickLoginApp(info, LoginAppTV)
+	assert.ErrorIs(t, err, ErrLoginAppSlotInUse)
+}
+
+func TestCredentialApp(t *testing.T) {
+	store := NewFileCredentialStore(filepath.Join(t.TempDir(), "cookie.json"))
+	assert.Nil(t, store.Save(&Credential{UID: "1", CID: "2", SEID: "3", App: LoginAppTV}))
+	cr, err := store.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, LoginAppTV, cr.App)
+	assert.Equal(t, LoginAppTV, New().ImportCredential(cr).Credential().App)
+}
+
+func TestVipLevel(t *testing.T) {
+	future := int(time.Now().Add(time.Hour).Unix())
+	past := int(time.Now().Add(-time.Hour).Unix())
+	assert.Equal(t, VipLevelNone, (&UserInfo{}).VipLevel())
+	assert.Equal(t, VipLevelVip, (&UserInfo{Vip: 1, Expire: future}).VipLevel())
+	assert.Equal(t, VipLevelExpired, (&UserInfo{Vip: 1, Expire: past}).VipLevel())
+	assert.Equal(t, VipLevelForever, (&UserInfo{Vip: 1, Forever: 1}).VipLevel())
+	assert.Equal(t, int64(future), (&UserInfo{Vip: 1, Expire: future}).VipExpire().Unix())
+	assert.True(t, (&UserInfo{Vip: 1, Expire: future, Forever: 1}).VipExpire().IsZero())
+}
+
+func TestGetAccount(t *testing.T) {
+	down := teardown(t)
+	defer down(t)
+	account, err := client.GetAccount(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, client.UserID, account.UserID)
+	assert.Equal(t, account.TotalSize, account.UsedSize+account.RemainSize)
+}
+
+func TestCollectOfflineTasks(t *testing.T) {
+	hashes := []string{"A", "b", "c"}
+	seen := make([]bool, len(hashes))
+	results := make([]*OfflineTask, len(hashes))
+
+	running := &OfflineTask{InfoHash: "a", Status: OfflineTaskStatusRunning}
+	finished, unseen := collectOfflineTasks(hashes, offlineTaskMap([]*OfflineTask{running}), seen, results)
+	assert.False(t, finished)
+	assert.Equal(t, []string{"b", "c"}, unseen)
+	assert.Equal(t, running, results[0])
+
+	done := &OfflineTask{InfoHash: "a", Status: OfflineTaskStatusDone}
+	b := &OfflineTask{InfoHash: "b", Status: OfflineTaskStatusFailed}
+	finished, unseen = collectOfflineTasks(hashes, offlineTaskMap([]*OfflineTask{done, b}), seen, results)
+	assert.False(t, finished)
+	assert.Equal(t, []string{"c"}, unseen)
+
+	c := &OfflineTask{InfoHash: "c", Status: OfflineTaskStatusDone}
+	finished, unseen = collectOfflineTasks(hashes, offlineTaskMap([]*OfflineTask{done, c}), seen, results)
+	assert.True(t, finished)
+	assert.Empty(t, unseen)
+	// b is removed after seen
+	assert.Nil(t, results[1])
+}
+
+func TestMatchRevertedFiles(t *testing.T) {
+	items := []RecycleBinItem{
+		{FileId: "r1", FileName: "a.txt", FileSize: 3},
+		{FileId: "r2", FileName: "dir"},
+		{FileId: "r3", FileName: "gone.txt", FileSize: 1},
+	}
+	files := []File{
+		{FileID: "f0", Name: "a.txt", Size: 4},
+		{FileID: "f1", Name: "a.txt", Size: 3},
+		{FileID: "f2", Name: "dir", IsDirectory: true},
+	}
+	fileIDs, missing := matchRevertedFiles(items, files)
+	assert.Equal(t, []string{"f1", "f2"}, fileIDs)
+	assert.Equal(t, []string{"gone.txt"}, missing)
+}
+
+func TestGetAccountCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := New()
+	_, err := c.GetAccount(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, c.UploadMetaInfo)
+}
+
+func TestBatchConcurrentRequests(t *testing.T) {
+	c := New()
+	c.Client.SetTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		w := httptest.NewRecorder()
+		_, _ = w.WriteString(`{"state":true}`)
+		resp := w.Result()
+		resp.Request = r
+		return resp, nil
+	}))
+	result := c.BatchDelete([]string{"1", "2", "3", "4", "5", "6", "7", "8"}, BatchWithChunkSize(1), BatchWithConcurrency(4))
+	assert.True(t, result.Ok())
+	assert.Len(t, result.Succeeded, 8)
+}
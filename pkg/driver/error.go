@@ -22,6 +22,8 @@ var (
 	ErrOfflineNoTimes     = errors.New("offline download quota has been used up, you can purchase a VIP experience or upgrade to VIP service to get more quota")
 	ErrOfflineInvalidLink = errors.New("invalid download link")
 	ErrOfflineTaskExisted = errors.New("offline task existed")
+	ErrBadTorrent         = errors.New("bad torrent")
+	ErrOfflineNoSpace     = errors.New("no space left for offline download")
 
 	ErrOrderNotSupport = errors.New("file order not supported")
 
@@ -31,7 +33,8 @@ var (
 	ErrCredentialInvalid    = errors.New("credential invalid")
 	ErrSessionExited        = errors.New("session exited")
 
-	ErrQrcodeExpired = errors.New("qrcode expired")
+	ErrQrcodeExpired  = errors.New("qrcode expired")
+	ErrQrcodeCanceled = errors.New("qrcode login canceled")
 
 	// ErrUnexpected is the fall-back error whose code is not handled.
 	ErrUnexpected = errors.New("unexpected error")
@@ -57,6 +60,8 @@ var (
 
 	ErrDownloadFileTooBig = errors.New("target file is too big to download")
 
+	ErrDownloadSha1Mismatch = errors.New("sha1 of downloaded file mismatch")
+
 	ErrCyclicCopy = errors.New("cyclic copy")
 
 	ErrCyclicMove = errors.New("cyclic move")
@@ -83,6 +88,10 @@ var (
 
 	ErrUploadSigInvalid = errors.New("sig invalid")
 
+	ErrNoAvailableAccount = errors.New("no available account in pool")
+
+	ErrLoginAppSlotInUse = errors.New("login app slot is in use, login will kick out the device")
+
 	errMap = map[int]error{
 		// Normal errors
 		99:     ErrNotLogin,
@@ -1,8 +1,11 @@
 package driver
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
+	"regexp"
 )
 
 func ExamplePan115Client_ImportCredential() {
@@ -130,3 +133,76 @@ func ExamplePan115Client_Mkdir() {
 	}
 	log.Printf("cid is  %s", cid)
 }
+
+func ExamplePan115Client_BatchDelete() {
+	client := Defalut()
+
+	result := client.BatchDelete([]string{"fileID1", "fileID2"}, BatchWithChunkSize(500))
+	for _, f := range result.Failed {
+		log.Printf("Delete file %s error: %s", f.ID, f.Err)
+	}
+}
+
+func ExamplePan115Client_RenameByRules() {
+	client := Defalut()
+
+	files, err := client.List("dirID")
+	if err != nil {
+		log.Fatalf("List file error: %s", err)
+	}
+	rules := []RenameRule{
+		RenameRegexp(regexp.MustCompile(`\[.*?\]`), ""),
+		RenameTemplate("Show S01E{n}{ext}", 1, 2),
+	}
+	for _, p := range PreviewRename(*files, rules...) {
+		log.Println(p)
+	}
+	_, result := client.RenameByRules(*files, rules)
+	if err := result.Err(); err != nil {
+		log.Fatalf("Rename file error: %s", err)
+	}
+}
+
+func ExamplePan115Client_RunOfflinePostProcess() {
+	client := Defalut()
+
+	rules := []*OfflinePostRule{{
+		Match:       OfflineTaskInDir("downloadDirID"),
+		JunkExts:    []string{".txt", ".url"},
+		SampleSize:  50 * MB,
+		Flatten:     true,
+		Rename:      []RenameRule{RenameToCase(RenameCaseLower)},
+		TargetDirID: "libraryDirID",
+	}}
+	err := client.RunOfflinePostProcess(context.Background(), rules, func(task *OfflineTask, err error) {
+		if err != nil {
+			log.Printf("Post process %s error: %s", task.Name, err)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Post process error: %s", err)
+	}
+}
+
+func ExamplePan115Client_LoginByQRCode() {
+	client := Defalut()
+
+	cr, err := client.LoginByQRCode(context.Background(), LoginAppTV, func(s *QRCodeSession) {
+		code, _ := s.QRCodeTerminal(true, false)
+		fmt.Println(code)
+	})
+	if err != nil {
+		log.Fatalf("Login error: %s", err)
+	}
+	log.Printf("cookie is %s", cr.Cookie())
+}
+
+func ExamplePan115Client_GetAccount() {
+	client := Defalut()
+
+	account, err := client.GetAccount(context.Background())
+	if err != nil {
+		log.Fatalf("Get account error: %s", err)
+	}
+	log.Printf("%s vip: %s, expire: %s, remain: %d bytes", account.UserName, account.VipLevel, account.VipExpire, account.RemainSize)
+}
@@ -24,8 +24,12 @@ type File struct {
 
 	// Is file stared
 	Star bool
+	// Is file hidden
+	Hidden bool
 	// File labels
 	Labels []*Label
+	// Description of the file, only filled when requested
+	Description string
 
 	// Create time of the file.
 	CreateTime time.Time
@@ -42,37 +46,22 @@ func (f *File) from(fileInfo *FileInfo) *File {
 		f.FileID = fileInfo.FileID
 		f.ParentID = string(fileInfo.CategoryID)
 		f.IsDirectory = false
-		loc, err := time.LoadLocation("Asia/Shanghai") // updatetime is a string without timezone
-		if err != nil {
-			// if missing Asia/Shanghai use CST（UTC+8） 
-			 loc = time.FixedZone("UTC+8", 8*3600)
-		}
-		localTime, err := time.ParseInLocation("2006-01-02 15:04", fileInfo.UpdateTime, loc)
-		if err == nil {
-			f.UpdateTime = time.Unix(localTime.Unix(), 0)
-		}
 	} else {
 		f.FileID = string(fileInfo.CategoryID)
 		f.ParentID = fileInfo.ParentID
 		f.IsDirectory = true
-		t, err := strconv.ParseInt(fileInfo.UpdateTime, 10, 64)
-		if err == nil {
-			f.UpdateTime = time.Unix(t, 0)
-		}
 	}
+	f.UpdateTime = parseUpdateTime(fileInfo.UpdateTime)
 	f.Name = fileInfo.Name
 	f.Size = int64(fileInfo.Size)
 	f.PickCode = fileInfo.PickCode
 	f.Sha1 = fileInfo.Sha1
 
 	f.Star = fileInfo.IsStar != 0
+	f.Hidden = fileInfo.IsHidden != 0
 	f.Labels = make([]*Label, len(fileInfo.Labels))
 	for i, l := range fileInfo.Labels {
-		f.Labels[i] = &Label{
-			ID:    l.ID,
-			Name:  l.Name,
-			Color: LabelColor(LabelColorMap[l.Color]),
-		}
+		f.Labels[i] = (&Label{}).from(l)
 	}
 
 	f.CreateTime = time.Unix(int64(fileInfo.CreateTime), 0)
@@ -80,6 +69,42 @@ func (f *File) from(fileInfo *FileInfo) *File {
 	return f
 }
 
+// FromShareFile converts a shared file into File
+func (f *File) FromShareFile(shareFile *ShareFile) *File {
+	f.IsDirectory = shareFile.IsDirectory()
+	f.FileID = shareFile.ID()
+	if f.IsDirectory {
+		f.ParentID = shareFile.ParentID
+	} else {
+		f.ParentID = string(shareFile.CategoryID)
+	}
+	f.Name = shareFile.FileName
+	f.Size = int64(shareFile.Size)
+	f.Sha1 = shareFile.Sha1
+	f.UpdateTime = parseUpdateTime(shareFile.UpdateTime)
+	f.Labels = make([]*Label, len(shareFile.Labels))
+	for i, l := range shareFile.Labels {
+		f.Labels[i] = (&Label{}).from(l)
+	}
+	return f
+}
+
+// parseUpdateTime parses update time which is a unix timestamp or a local time string
+func parseUpdateTime(updateTime string) time.Time {
+	if t, err := strconv.ParseInt(updateTime, 10, 64); err == nil {
+		return time.Unix(t, 0)
+	}
+	loc, err := time.LoadLocation("Asia/Shanghai") // updatetime is a string without timezone
+	if err != nil {
+		// if missing Asia/Shanghai use CST（UTC+8）
+		loc = time.FixedZone("UTC+8", 8*3600)
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", updateTime, loc); err == nil {
+		return time.Unix(t.Unix(), 0)
+	}
+	return time.Time{}
+}
+
 func (f File) GetPath() string {
 	return ""
 }
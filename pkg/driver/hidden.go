@@ -0,0 +1,81 @@
+package driver
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// HideFiles hide files or directories
+func (c *Pan115Client) HideFiles(fileIDs ...string) error {
+	return c.setHidden(true, fileIDs...)
+}
+
+// UnhideFiles unhide files or directories
+func (c *Pan115Client) UnhideFiles(fileIDs ...string) error {
+	return c.setHidden(false, fileIDs...)
+}
+
+func (c *Pan115Client) setHidden(hidden bool, fileIDs ...string) error {
+	if len(fileIDs) == 0 {
+		return nil
+	}
+	form := map[string]string{
+		"hidden": strconv.Itoa(BoolToInt(hidden)),
+	}
+	for i, value := range fileIDs {
+		key := fmt.Sprintf("%s[%d]", "fid", i)
+		form[key] = value
+	}
+
+	result := BasicResp{}
+	req := c.NewRequest().
+		SetFormData(form).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Post(ApiFileHide)
+	return CheckErr(err, &result, resp)
+}
+
+// EnterHiddenMode shows hidden files in list with the secret password.
+// Hidden mode is a state of the account on server, it affects all sessions of the account,
+// including other clients and the web page, until ExitHiddenMode is called.
+func (c *Pan115Client) EnterHiddenMode(password string) error {
+	return c.switchHiddenMode(map[string]string{
+		"show":       "1",
+		"safe_pwd":   password,
+		"valid_type": "1",
+	})
+}
+
+// ExitHiddenMode stops showing hidden files in list, for all sessions of the account
+func (c *Pan115Client) ExitHiddenMode() error {
+	return c.switchHiddenMode(map[string]string{
+		"show":       "0",
+		"valid_type": "1",
+	})
+}
+
+func (c *Pan115Client) switchHiddenMode(form map[string]string) error {
+	result := BasicResp{}
+	req := c.NewRequest().
+		SetFormData(form).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Post(ApiHiddenSwitch)
+	return CheckErr(err, &result, resp)
+}
+
+// inHiddenMode runs fn in hidden mode when password is not empty, and exits hidden mode afterwards
+func (c *Pan115Client) inHiddenMode(password string, fn func() error) error {
+	if password == "" {
+		return fn()
+	}
+	if err := c.EnterHiddenMode(password); err != nil {
+		return err
+	}
+	err := fn()
+	if exitErr := c.ExitHiddenMode(); err == nil {
+		err = exitErr
+	}
+	return err
+}
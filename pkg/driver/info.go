@@ -1,9 +1,16 @@
 package driver
 
+import "context"
+
 // GetInfo get space info and login device info.
 func (c *Pan115Client) GetInfo() (InfoData, error) {
+	return c.getInfo(context.Background())
+}
+
+func (c *Pan115Client) getInfo(ctx context.Context) (InfoData, error) {
 	result := InfoResponse{}
 	req := c.NewRequest().
+		SetContext(ctx).
 		SetResult(&result).
 		ForceContentType("application/json;charset=UTF-8")
 
@@ -1,5 +1,11 @@
 package driver
 
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
 var (
 	LabelColors = []string{
 		// No Color
@@ -38,4 +44,205 @@ type Label struct {
 	Color LabelColor
 }
 
+func (l *Label) from(info *LabelInfo) *Label {
+	l.ID = info.ID
+	l.Name = info.Name
+	l.Color = LabelColor(LabelColorMap[strings.ToUpper(info.Color)])
+	return l
+}
+
 type LabelColor int
+
+// Hex returns color in hex format, falls back to no color
+func (c LabelColor) Hex() string {
+	if c < 0 || int(c) >= len(LabelColors) {
+		return LabelColors[0]
+	}
+	return LabelColors[c]
+}
+
+const LabelListLimit = 11500
+
+// ListLabels list all labels
+func (c *Pan115Client) ListLabels() ([]*Label, error) {
+	result := LabelListResp{}
+	req := c.NewRequest().
+		SetQueryParams(map[string]string{
+			"offset": "0",
+			"limit":  strconv.Itoa(LabelListLimit),
+			"sort":   "",
+			"order":  "",
+		}).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Get(ApiLabelList)
+	if err = CheckErr(err, &result, resp); err != nil {
+		return nil, err
+	}
+	labels := make([]*Label, len(result.Data.List))
+	for i, l := range result.Data.List {
+		labels[i] = (&Label{}).from(l)
+	}
+	return labels, nil
+}
+
+// CreateLabel create a label with name and color
+func (c *Pan115Client) CreateLabel(name string, color LabelColor) (*Label, error) {
+	result := LabelAddResp{}
+	req := c.NewRequest().
+		SetFormData(map[string]string{
+			"name[]": name + "\x07" + color.Hex(),
+		}).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Post(ApiLabelAdd)
+	if err = CheckErr(err, &result, resp); err != nil {
+		return nil, err
+	}
+	if len(result.Data) == 0 {
+		return nil, ErrUnexpected
+	}
+	return (&Label{}).from(result.Data[0]), nil
+}
+
+// RenameLabel rename a label
+func (c *Pan115Client) RenameLabel(labelID, name string) error {
+	return c.editLabel(map[string]string{
+		"id":   labelID,
+		"name": name,
+	})
+}
+
+// RecolorLabel change color of a label
+func (c *Pan115Client) RecolorLabel(labelID string, color LabelColor) error {
+	return c.editLabel(map[string]string{
+		"id":    labelID,
+		"color": color.Hex(),
+	})
+}
+
+func (c *Pan115Client) editLabel(form map[string]string) error {
+	result := BasicResp{}
+	req := c.NewRequest().
+		SetFormData(form).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Post(ApiLabelEdit)
+	return CheckErr(err, &result, resp)
+}
+
+// DeleteLabels delete labels
+func (c *Pan115Client) DeleteLabels(labelIDs ...string) error {
+	if len(labelIDs) == 0 {
+		return nil
+	}
+	result := BasicResp{}
+	req := c.NewRequest().
+		SetFormData(map[string]string{
+			"id": strings.Join(labelIDs, ","),
+		}).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Post(ApiLabelDelete)
+	return CheckErr(err, &result, resp)
+}
+
+type LabelAction string
+
+const (
+	LabelActionAdd    LabelAction = "add"
+	LabelActionRemove LabelAction = "remove"
+	// LabelActionReset replaces all labels of files with given labels
+	LabelActionReset LabelAction = "reset"
+)
+
+// AddFileLabels attach labels to files
+func (c *Pan115Client) AddFileLabels(fileIDs []string, labelIDs ...string) error {
+	return c.SetFileLabels(LabelActionAdd, fileIDs, labelIDs...)
+}
+
+// RemoveFileLabels detach labels from files
+func (c *Pan115Client) RemoveFileLabels(fileIDs []string, labelIDs ...string) error {
+	return c.SetFileLabels(LabelActionRemove, fileIDs, labelIDs...)
+}
+
+// SetFileLabels change labels of files with action
+func (c *Pan115Client) SetFileLabels(action LabelAction, fileIDs []string, labelIDs ...string) error {
+	if len(fileIDs) == 0 {
+		return nil
+	}
+	form := url.Values{}
+	form.Set("action", string(action))
+	form.Set("file_ids", strings.Join(fileIDs, ","))
+	form.Set("file_label", strings.Join(labelIDs, ","))
+
+	result := BasicResp{}
+	req := c.NewRequest().
+		SetFormDataFromValues(form).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Post(ApiFileLabel)
+	return CheckErr(err, &result, resp)
+}
+
+// Star star or unstar files
+func (c *Pan115Client) Star(star bool, fileIDs ...string) error {
+	if len(fileIDs) == 0 {
+		return nil
+	}
+	result := BasicResp{}
+	req := c.NewRequest().
+		SetFormData(map[string]string{
+			"file_id": strings.Join(fileIDs, ","),
+			"star":    strconv.Itoa(BoolToInt(star)),
+		}).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Post(ApiFileStar)
+	return CheckErr(err, &result, resp)
+}
+
+// ListByLabel list all files and directories with label
+func (c *Pan115Client) ListByLabel(labelID string) (*[]File, error) {
+	var files []File
+	limit := int64(MaxDirPageLimit)
+	for offset := int64(0); ; offset += limit {
+		result := FileListResp{}
+		req := c.NewRequest().
+			SetQueryParams(map[string]string{
+				"aid":          "1",
+				"cid":          "0",
+				"file_label":   labelID,
+				"search_value": "",
+				"offset":       strconv.FormatInt(offset, 10),
+				"limit":        strconv.FormatInt(limit, 10),
+				"format":       "json",
+			}).
+			ForceContentType("application/json;charset=UTF-8").
+			SetResult(&result)
+		resp, err := req.Get(ApiFileSearch)
+		if err = CheckErr(err, &result, resp); err != nil {
+			return nil, err
+		}
+		for _, fileInfo := range result.Files {
+			files = append(files, *(&File{}).from(&fileInfo))
+		}
+		if len(result.Files) == 0 || offset+limit >= int64(result.Count) {
+			break
+		}
+	}
+	return &files, nil
+}
+
+type LabelListResp struct {
+	BasicResp
+	Data struct {
+		List  []*LabelInfo `json:"list"`
+		Total int          `json:"total"`
+	} `json:"data"`
+}
+
+type LabelAddResp struct {
+	BasicResp
+	Data []*LabelInfo `json:"data"`
+}
@@ -1,6 +1,7 @@
 package driver
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	neturl "net/url"
@@ -11,6 +12,14 @@ import (
 
 // CookieCheck checks the cookie status and will not logout of other devices.
 func (c *Pan115Client) CookieCheck() error {
+	if valid, _ := c.cookieCheck(); !valid {
+		return ErrBadCookie
+	}
+	return nil
+}
+
+// cookieCheck tells a request error apart from an invalid cookie.
+func (c *Pan115Client) cookieCheck() (bool, error) {
 	result := struct {
 		State bool `json:"state"`
 	}{}
@@ -18,10 +27,14 @@ func (c *Pan115Client) CookieCheck() error {
 		SetQueryParam("_", NowMilli().String()).
 		SetResult(&result)
 
-	if _, _ = req.Get(ApiStatusCheck); !result.State {
-		return ErrBadCookie
+	resp, err := req.Get(ApiStatusCheck)
+	if err == nil && resp.IsError() {
+		err = fmt.Errorf("status check: %s", resp.Status())
 	}
-	return nil
+	if err != nil {
+		return false, err
+	}
+	return result.State, nil
 }
 
 // LoginCheck checks the login status and will logout of other devices.
@@ -47,6 +60,10 @@ func (c *Pan115Client) ImportCredential(cr *Credential) *Pan115Client {
 		CookieNameKid:  cr.KID,
 	}
 	c.ImportCookies(cookies, CookieDomain115)
+	c.credentialMu.Lock()
+	imported := *cr
+	c.credential = &imported
+	c.credentialMu.Unlock()
 	return c
 }
 
@@ -88,6 +105,8 @@ type Credential struct {
 	CID  string `json:"CID"`
 	SEID string `json:"SEID"`
 	KID  string `json:"KID"`
+	// App is the login app which the credential belongs to, empty if unknown.
+	App LoginApp `json:"app,omitempty"`
 }
 
 // FromCookie get uid, cid, seid from cookie string
@@ -125,10 +144,28 @@ func (cr *Credential) Cookie() string {
 
 // GetUser get user information
 func (c *Pan115Client) GetUser() (*UserInfo, error) {
+	return c.getUser(context.Background())
+}
+
+func (c *Pan115Client) getUser(ctx context.Context) (*UserInfo, error) {
 	result := UserInfoResp{}
 	req := c.NewRequest().
+		SetContext(ctx).
 		SetQueryParam("_", Now().String()).
 		SetResult(&result)
 	resp, err := req.Get(ApiUserInfo)
 	return &result.UserInfo, CheckErr(err, &result, resp)
 }
+
+// ensureUserID fills UserID by user information when it is unknown
+func (c *Pan115Client) ensureUserID() error {
+	if c.UserID > 0 {
+		return nil
+	}
+	userInfo, err := c.GetUser()
+	if err != nil {
+		return err
+	}
+	c.UserID = userInfo.UserID
+	return nil
+}
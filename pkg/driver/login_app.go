@@ -0,0 +1,93 @@
+package driver
+
+import (
+	"github.com/pkg/errors"
+)
+
+// loginAppSsoents maps login apps to device slots, a login kicks out the device in the same slot.
+var loginAppSsoents = map[LoginApp]string{
+	LoginAppWeb:        "A1",
+	LoginAppIOS:        "D1",
+	LoginAppAndroid:    "F1",
+	LoginAppTV:         "I1",
+	LoginQAppAndroid:   "M1",
+	LoginAppWechatMini: "R1",
+	LoginAppAlipayMini: "R2",
+}
+
+// DefaultLoginAppPreference is the order to pick login apps, slots which people use rarely come first.
+var DefaultLoginAppPreference = []LoginApp{
+	LoginAppTV,
+	LoginAppAlipayMini,
+	LoginAppWechatMini,
+	LoginQAppAndroid,
+	LoginAppAndroid,
+	LoginAppIOS,
+	LoginAppWeb,
+}
+
+// Ssoent returns the device slot of the app, empty if unknown.
+func (app LoginApp) Ssoent() string {
+	return loginAppSsoents[app]
+}
+
+// LoginAppBySsoent returns the login app of a device slot.
+func LoginAppBySsoent(ssoent string) (LoginApp, bool) {
+	for app, s := range loginAppSsoents {
+		if s == ssoent {
+			return app, true
+		}
+	}
+	return "", false
+}
+
+// LoginApp returns the login app of the device.
+func (d *Device) LoginApp() (LoginApp, bool) {
+	return LoginAppBySsoent(d.Ssoent)
+}
+
+// deviceInSlot returns the device which is logged in with app.
+func (info *LoginDevicesInfo) deviceInSlot(app LoginApp) *Device {
+	for i := range info.List {
+		if info.List[i].Ssoent == app.Ssoent() {
+			return &info.List[i]
+		}
+	}
+	return nil
+}
+
+// PickLoginApp returns the first app in preferred whose slot is not in use, DefaultLoginAppPreference if preferred is empty.
+func PickLoginApp(info *LoginDevicesInfo, preferred ...LoginApp) (LoginApp, error) {
+	if len(preferred) == 0 {
+		preferred = DefaultLoginAppPreference
+	}
+	for _, app := range preferred {
+		if app.Ssoent() != "" && info.deviceInSlot(app) == nil {
+			return app, nil
+		}
+	}
+	return "", ErrLoginAppSlotInUse
+}
+
+// PickLoginApp picks a login app with an unused slot by login devices of current account,
+// so a new login of the account does not kick out any session.
+func (c *Pan115Client) PickLoginApp(preferred ...LoginApp) (LoginApp, error) {
+	info, err := c.GetInfo()
+	if err != nil {
+		return "", err
+	}
+	return PickLoginApp(&info.LoginDevicesInfo, preferred...)
+}
+
+// CheckLoginAppSlot returns ErrLoginAppSlotInUse if a device of current account is logged in with app,
+// including the current client itself.
+func (c *Pan115Client) CheckLoginAppSlot(app LoginApp) error {
+	info, err := c.GetInfo()
+	if err != nil {
+		return err
+	}
+	if device := info.LoginDevicesInfo.deviceInSlot(app); device != nil {
+		return errors.Wrapf(ErrLoginAppSlotInUse, "%s is logged in with %s", device.Name, app)
+	}
+	return nil
+}
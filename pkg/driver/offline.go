@@ -28,20 +28,27 @@ type OfflineTask struct {
 	Move         int     `json:"move"`
 }
 
+const (
+	OfflineTaskStatusFailed  = -1
+	OfflineTaskStatusTodo    = 0
+	OfflineTaskStatusRunning = 1
+	OfflineTaskStatusDone    = 2
+)
+
 func (t *OfflineTask) IsTodo() bool {
-	return t.Status == 0
+	return t.Status == OfflineTaskStatusTodo
 }
 
 func (t *OfflineTask) IsRunning() bool {
-	return t.Status == 1
+	return t.Status == OfflineTaskStatusRunning
 }
 
 func (t *OfflineTask) IsDone() bool {
-	return t.Status == 2
+	return t.Status == OfflineTaskStatusDone
 }
 
 func (t *OfflineTask) IsFailed() bool {
-	return t.Status == -1
+	return t.Status == OfflineTaskStatusFailed
 }
 
 func (t *OfflineTask) GetStatus() string {
@@ -79,14 +86,14 @@ func (c *Pan115Client) ListOfflineTask(page int64) (OfflineTaskResp, error) {
 	return result, nil
 }
 
-// AddOfflineTaskURIs adds offline tasks by download URIs.
-// supports http, ed2k, magent
+// AddOfflineTaskURIs adds offline tasks by download URIs, the first rejected URI fails the call,
+// see AddOfflineTaskURIsWithResults for results per URI.
+// supports http, ed2k, magent, use WithPreflight to check links and quota before adding
 func (c *Pan115Client) AddOfflineTaskURIs(uris []string, saveDirID string, opts ...OfflineOption) (hashes []string, err error) {
 	if isCalledByAlistV3() {
 		return nil, ErrorNotSupportAlist
 	}
 	opt := DefaultOfflineOptions()
-
 	for _, o := range opts {
 		o(&opt)
 	}
@@ -94,62 +101,167 @@ func (c *Pan115Client) AddOfflineTaskURIs(uris []string, saveDirID string, opts
 	if count == 0 {
 		return
 	}
-
-	if c.UserID <= 0 {
-		userInfo, err := c.GetUser()
-		if err != nil {
+	if opt.preflight {
+		if err := c.PreflightOfflineTasks(uris); err != nil {
 			return nil, err
 		}
-		c.UserID = userInfo.UserID
 	}
 
-	key := crypto.GenerateKey()
+	results, err := c.addOfflineTaskURIs(uris, saveDirID, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes = make([]string, count)
+	for i, result := range results {
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		hashes[i] = result.InfoHash
+	}
+	return hashes, nil
+}
+
+// OfflineAddResult describes the result of adding an offline task by an URI.
+type OfflineAddResult struct {
+	URI      string
+	InfoHash string
+	// Err is mapped from error code, e.g. ErrOfflineTaskExisted or ErrOfflineInvalidLink.
+	Err error
+}
+
+// AddOfflineTaskURIsWithResults adds offline tasks by download URIs and returns a result per URI in order of uris,
+// rejected URIs do not fail the whole call. With WithPreflight, links with bad syntax are rejected locally.
+func (c *Pan115Client) AddOfflineTaskURIsWithResults(uris []string, saveDirID string, opts ...OfflineOption) ([]*OfflineAddResult, error) {
+	if isCalledByAlistV3() {
+		return nil, ErrorNotSupportAlist
+	}
+	opt := DefaultOfflineOptions()
+	for _, o := range opts {
+		o(&opt)
+	}
+	results := make([]*OfflineAddResult, len(uris))
+	var valid []string
+	for i, uri := range uris {
+		results[i] = &OfflineAddResult{URI: uri}
+		if opt.preflight {
+			if results[i].Err = ValidateOfflineURI(uri); results[i].Err != nil {
+				continue
+			}
+		}
+		valid = append(valid, uri)
+	}
+	if len(valid) == 0 {
+		return results, nil
+	}
+	if opt.preflight {
+		if err := c.checkOfflineQuota(len(valid)); err != nil {
+			return nil, err
+		}
+	}
 
-	result := DownloadResp{}
+	added, err := c.addOfflineTaskURIs(valid, saveDirID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, 0; i < len(results); i++ {
+		if results[i].Err == nil {
+			results[i] = added[j]
+			j++
+		}
+	}
+	return results, nil
+}
 
+func (c *Pan115Client) addOfflineTaskURIs(uris []string, saveDirID string, opts ...OfflineOption) ([]*OfflineAddResult, error) {
 	params := map[string]string{
 		"ac":         "add_task_urls",
 		"wp_path_id": saveDirID,
-		"app_ver":    opt.appVer,
-		"uid":        strconv.FormatInt(c.UserID, 10),
 	}
 	for i, uri := range uris {
 		key := fmt.Sprintf("url[%d]", i)
 		params[key] = uri
 	}
+
+	taskInfos := OfflineAddUrlResponse{}
+	if err := c.postLixianSSP(ApiAddOfflineUrl, params, &taskInfos, opts...); err != nil {
+		return nil, err
+	}
+	return matchOfflineAddResults(uris, &taskInfos)
+}
+
+// matchOfflineAddResults maps results of an add request to uris, a call-wide failure is returned as error.
+func matchOfflineAddResults(uris []string, taskInfos *OfflineAddUrlResponse) ([]*OfflineAddResult, error) {
+	if len(taskInfos.Result) == 0 {
+		// e.g. quota is used up
+		if err := taskInfos.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	// match by url, falls back to position
+	tasks := map[string][]*OfflineTaskResponse{}
+	for i := range taskInfos.Result {
+		task := &taskInfos.Result[i]
+		tasks[task.Url] = append(tasks[task.Url], task)
+	}
+	results := make([]*OfflineAddResult, len(uris))
+	for i, uri := range uris {
+		var task *OfflineTaskResponse
+		if matched := tasks[uri]; len(matched) > 0 {
+			task, tasks[uri] = matched[0], matched[1:]
+		} else if len(taskInfos.Result) == len(uris) {
+			task = &taskInfos.Result[i]
+		}
+		results[i] = &OfflineAddResult{URI: uri}
+		if task == nil {
+			results[i].Err = GetErr(0, "no result of uri")
+			continue
+		}
+		results[i].InfoHash = task.InfoHash
+		results[i].Err = task.Err()
+	}
+	return results, nil
+}
+
+// postLixianSSP posts encrypted params to lixianssp api and decodes the decrypted response into result
+func (c *Pan115Client) postLixianSSP(apiURL string, params map[string]string, result any, opts ...OfflineOption) error {
+	opt := DefaultOfflineOptions()
+	for _, o := range opts {
+		o(&opt)
+	}
+	if err := c.ensureUserID(); err != nil {
+		return err
+	}
+	params["app_ver"] = opt.appVer
+	params["uid"] = strconv.FormatInt(c.UserID, 10)
+
+	key := crypto.GenerateKey()
 	paramsBytes, err := json.Marshal(params)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
+	encoded := DownloadResp{}
 	data := crypto.Encode(paramsBytes, key)
 	req := c.NewRequest().
 		SetQueryParam("t", Now().String()).
 		SetFormData(map[string]string{"data": data}).
 		ForceContentType("application/json").
-		SetResult(&result)
+		SetResult(&encoded)
 
-	resp, err := req.Post(ApiAddOfflineUrl)
+	resp, err := req.Post(apiURL)
 
-	if err := CheckErr(err, &result, resp); err != nil {
-		return nil, err
+	// data is returned even if some of tasks failed
+	if err != nil || encoded.EncodedData == "" {
+		return CheckErr(err, &encoded, resp)
 	}
 
-	bytes, err := crypto.Decode(string(result.EncodedData), key)
+	bytes, err := crypto.Decode(string(encoded.EncodedData), key)
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	taskInfos := OfflineAddUrlResponse{}
-	if err := json.Unmarshal(bytes, &taskInfos); err != nil {
-		return nil, err
-	}
-
-	hashes = make([]string, count)
-	for i, task := range taskInfos.Result {
-		hashes[i] = task.InfoHash
-	}
-	return hashes, nil
+	return json.Unmarshal(bytes, result)
 }
 
 // DeleteOfflineTasks deletes tasks.
@@ -199,6 +311,16 @@ type OfflineAddUrlResponse struct {
 type OfflineTaskResponse struct {
 	InfoHash string `json:"info_hash"`
 	Url      string `json:"url"`
+	State    bool   `json:"state"`
+	ErrCode  int    `json:"errcode"`
+	ErrorMsg string `json:"error_msg"`
+}
+
+func (resp *OfflineTaskResponse) Err(respBody ...string) error {
+	if resp.State || (resp.ErrCode == 0 && resp.InfoHash != "") {
+		return nil
+	}
+	return GetErr(resp.ErrCode, resp.ErrorMsg)
 }
 
 type OfflineTaskResp struct {
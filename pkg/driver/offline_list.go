@@ -0,0 +1,141 @@
+package driver
+
+import (
+	"strings"
+)
+
+// OfflineTaskFilter returns true if the task should be kept
+type OfflineTaskFilter func(t *OfflineTask) bool
+
+// OfflineTaskWithStatus keeps tasks in any of statuses
+func OfflineTaskWithStatus(statuses ...int) OfflineTaskFilter {
+	return func(t *OfflineTask) bool {
+		for _, status := range statuses {
+			if t.Status == status {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// OfflineTaskInDir keeps tasks saved into directory
+func OfflineTaskInDir(dirID string) OfflineTaskFilter {
+	return func(t *OfflineTask) bool {
+		return t.DirId == dirID
+	}
+}
+
+// OfflineTaskNameContains keeps tasks whose name contains substr, case insensitive
+func OfflineTaskNameContains(substr string) OfflineTaskFilter {
+	substr = strings.ToLower(substr)
+	return func(t *OfflineTask) bool {
+		return strings.Contains(strings.ToLower(t.Name), substr)
+	}
+}
+
+func matchOfflineTask(t *OfflineTask, filters []OfflineTaskFilter) bool {
+	for _, filter := range filters {
+		if !filter(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// OfflineTaskIterator iterates offline tasks across pages.
+//
+//	it := client.OfflineTasks(OfflineTaskWithStatus(OfflineTaskStatusDone))
+//	for it.Next() {
+//		task := it.Task()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type OfflineTaskIterator struct {
+	client  *Pan115Client
+	filters []OfflineTaskFilter
+
+	page      int64
+	pageCount int64
+	tasks     []*OfflineTask
+	task      *OfflineTask
+	err       error
+}
+
+// OfflineTasks returns an iterator of offline tasks which match all filters
+func (c *Pan115Client) OfflineTasks(filters ...OfflineTaskFilter) *OfflineTaskIterator {
+	it := &OfflineTaskIterator{
+		client:    c,
+		filters:   filters,
+		pageCount: 1,
+	}
+	if isCalledByAlistV3() {
+		it.err = ErrorNotSupportAlist
+	}
+	return it
+}
+
+// Next advances to the next task, returns false when there is no more task or an error occurs
+func (it *OfflineTaskIterator) Next() bool {
+	for it.err == nil {
+		for len(it.tasks) > 0 {
+			it.task, it.tasks = it.tasks[0], it.tasks[1:]
+			if matchOfflineTask(it.task, it.filters) {
+				return true
+			}
+		}
+		if it.page >= it.pageCount {
+			break
+		}
+		it.page++
+		result, err := it.client.ListOfflineTask(it.page)
+		if err != nil {
+			it.err = err
+			break
+		}
+		it.pageCount = result.PageCount
+		it.tasks = result.Tasks
+	}
+	it.task = nil
+	return false
+}
+
+// Task returns the current task
+func (it *OfflineTaskIterator) Task() *OfflineTask {
+	return it.task
+}
+
+// Err returns the error occurred in iteration
+func (it *OfflineTaskIterator) Err() error {
+	return it.err
+}
+
+// ListAllOfflineTasks list tasks of all pages which match all filters
+func (c *Pan115Client) ListAllOfflineTasks(filters ...OfflineTaskFilter) ([]*OfflineTask, error) {
+	if isCalledByAlistV3() {
+		return nil, ErrorNotSupportAlist
+	}
+	var tasks []*OfflineTask
+	it := c.OfflineTasks(filters...)
+	for it.Next() {
+		tasks = append(tasks, it.Task())
+	}
+	return tasks, it.Err()
+}
+
+// GetOfflineTaskByHash get task by info hash
+func (c *Pan115Client) GetOfflineTaskByHash(infoHash string) (*OfflineTask, error) {
+	if isCalledByAlistV3() {
+		return nil, ErrorNotSupportAlist
+	}
+	it := c.OfflineTasks(func(t *OfflineTask) bool {
+		return strings.EqualFold(t.InfoHash, infoHash)
+	})
+	if it.Next() {
+		return it.Task(), nil
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return nil, ErrNotExist
+}
@@ -0,0 +1,146 @@
+package driver
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// OfflinePostRule describes chores to run on the result of a finished offline task.
+// Steps run in order: delete junk, flatten, rename, move.
+type OfflinePostRule struct {
+	// Match selects tasks, nil matches all tasks.
+	Match OfflineTaskFilter
+	// JunkExts deletes files with these extensions in the result directory, e.g. ".txt", ".url".
+	JunkExts []string
+	// SampleSize deletes files whose name contains "sample" and size is less than it, 0 disables.
+	SampleSize int64
+	// Flatten moves the only file out of the result directory and deletes the directory.
+	Flatten bool
+	// Rename renames the result file or directory.
+	Rename []RenameRule
+	// TargetDirID moves the result into it, empty keeps it in the save directory.
+	TargetDirID string
+}
+
+func (r *OfflinePostRule) isJunk(f *File) bool {
+	if f.IsDirectory {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(f.Name))
+	for _, junk := range r.JunkExts {
+		if ext == strings.ToLower(junk) {
+			return true
+		}
+	}
+	return r.SampleSize > 0 && f.Size < r.SampleSize &&
+		strings.Contains(strings.ToLower(f.Name), "sample")
+}
+
+// PostProcessOfflineTask runs rule on the result of a finished offline task
+func (c *Pan115Client) PostProcessOfflineTask(task *OfflineTask, rule *OfflinePostRule) error {
+	if isCalledByAlistV3() {
+		return ErrorNotSupportAlist
+	}
+	if task.FileId == "" {
+		return errors.Wrap(ErrNotExist, "result of offline task "+task.InfoHash)
+	}
+	f, err := c.GetFile(task.FileId)
+	if err != nil {
+		return err
+	}
+
+	if f.IsDirectory {
+		var junk []string
+		if err := c.walkDir(f.FileID, func(file *File) {
+			if rule.isJunk(file) {
+				junk = append(junk, file.FileID)
+			}
+		}); err != nil {
+			return err
+		}
+		if err := c.BatchDelete(junk).Err(); err != nil {
+			return err
+		}
+
+		if rule.Flatten {
+			files, err := c.List(f.FileID)
+			if err != nil {
+				return err
+			}
+			if len(*files) == 1 && !(*files)[0].IsDirectory {
+				only := (*files)[0]
+				if err := c.Move(f.ParentID, only.FileID); err != nil {
+					return err
+				}
+				if err := c.Delete(f.FileID); err != nil {
+					return err
+				}
+				only.ParentID = f.ParentID
+				f = &only
+			}
+		}
+	}
+
+	if previews := PreviewRename([]File{*f}, rule.Rename...); len(previews) > 0 {
+		if err := c.Rename(f.FileID, previews[0].NewName); err != nil {
+			return err
+		}
+	}
+
+	if rule.TargetDirID != "" && rule.TargetDirID != f.ParentID {
+		return c.Move(rule.TargetDirID, f.FileID)
+	}
+	return nil
+}
+
+// walkDir calls fn on every file and directory under dirID recursively
+func (c *Pan115Client) walkDir(dirID string, fn func(f *File)) error {
+	files, err := c.List(dirID)
+	if err != nil {
+		return err
+	}
+	for i := range *files {
+		f := &(*files)[i]
+		fn(f)
+		if f.IsDirectory {
+			if err := c.walkDir(f.FileID, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RunOfflinePostProcess watches offline tasks and runs the first matched rule on every task once it is done,
+// onResult is called after every run. A task is processed at most once even if it is reported done again.
+// It blocks until ctx is done.
+func (c *Pan115Client) RunOfflinePostProcess(ctx context.Context, rules []*OfflinePostRule, onResult func(task *OfflineTask, err error), opts ...OfflinePollOption) error {
+	if isCalledByAlistV3() {
+		return ErrorNotSupportAlist
+	}
+	processed := map[string]bool{}
+	for e := range c.WatchOfflineTasks(ctx, opts...) {
+		if e.Type != OfflineTaskEventDone {
+			continue
+		}
+		hash := strings.ToLower(e.Task.InfoHash)
+		if processed[hash] {
+			continue
+		}
+		processed[hash] = true
+		for _, rule := range rules {
+			if rule.Match != nil && !rule.Match(e.Task) {
+				continue
+			}
+			err := c.PostProcessOfflineTask(e.Task, rule)
+			if onResult != nil {
+				onResult(e.Task, err)
+			}
+			break
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,169 @@
+package driver
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// OfflineQuota describes offline download quota of current month
+type OfflineQuota struct {
+	Total  int64
+	Used   int64
+	Remain int64
+	// SizeLimit is the max size in bytes of a single task, 0 if unknown.
+	SizeLimit int64
+	Packages  []OfflineQuotaPackage
+}
+
+type OfflineQuotaPackage struct {
+	Name   string
+	Total  int64
+	Used   int64
+	Remain int64
+}
+
+// GetOfflineQuota get offline download quota
+func (c *Pan115Client) GetOfflineQuota() (*OfflineQuota, error) {
+	return c.getOfflineQuota(context.Background())
+}
+
+func (c *Pan115Client) getOfflineQuota(ctx context.Context) (*OfflineQuota, error) {
+	result := OfflineQuotaResp{}
+	req := c.NewRequest().
+		SetContext(ctx).
+		SetResult(&result).
+		ForceContentType("application/json;charset=UTF-8")
+	resp, err := req.Get(ApiOfflineQuota)
+	if err := CheckErr(err, &result, resp); err != nil {
+		return nil, err
+	}
+
+	space := OfflineSpaceResp{}
+	req = c.NewRequest().
+		SetContext(ctx).
+		SetQueryParam("_", Now().String()).
+		SetResult(&space).
+		ForceContentType("application/json;charset=UTF-8")
+	resp, err = req.Get(ApiOfflineSpace)
+	if err := CheckErr(err, &space, resp); err != nil {
+		return nil, err
+	}
+
+	quota := &OfflineQuota{
+		Total:     int64(result.Count),
+		Used:      int64(result.Used),
+		Remain:    int64(result.Surplus),
+		SizeLimit: int64(space.Limit),
+		Packages:  make([]OfflineQuotaPackage, len(result.Packages)),
+	}
+	for i, p := range result.Packages {
+		quota.Packages[i] = OfflineQuotaPackage{
+			Name:   p.Name,
+			Total:  int64(p.Count),
+			Used:   int64(p.Used),
+			Remain: int64(p.Surplus),
+		}
+	}
+	return quota, nil
+}
+
+// PreflightOfflineTasks validates links locally, then checks offline quota and free space
+func (c *Pan115Client) PreflightOfflineTasks(uris []string) error {
+	for _, uri := range uris {
+		if err := ValidateOfflineURI(uri); err != nil {
+			return err
+		}
+	}
+	return c.checkOfflineQuota(len(uris))
+}
+
+// checkOfflineQuota checks offline quota of count tasks and free space
+func (c *Pan115Client) checkOfflineQuota(count int) error {
+	quota, err := c.GetOfflineQuota()
+	if err != nil {
+		return err
+	}
+	if quota.Remain < int64(count) {
+		return ErrOfflineNoTimes
+	}
+	info, err := c.GetInfo()
+	if err != nil {
+		return err
+	}
+	if info.SpaceInfo.AllRemain.Size <= 0 {
+		return ErrOfflineNoSpace
+	}
+	return nil
+}
+
+var ed2kRegexp = regexp.MustCompile(`(?i)^ed2k://\|file\|[^|]+\|\d+\|[0-9a-f]{32}\|`)
+
+// ValidateOfflineURI checks syntax of a http, ftp, ed2k or magnet link
+func ValidateOfflineURI(uri string) error {
+	uri = strings.TrimSpace(uri)
+	invalid := errors.Wrap(ErrOfflineInvalidLink, uri)
+	lower := strings.ToLower(uri)
+	switch {
+	case strings.HasPrefix(lower, "magnet:"):
+		u, err := url.Parse(uri)
+		if err != nil {
+			return invalid
+		}
+		for _, xt := range u.Query()["xt"] {
+			if strings.HasPrefix(strings.ToLower(xt), "urn:btih:") && isBtih(xt[len("urn:btih:"):]) {
+				return nil
+			}
+		}
+		return invalid
+	case strings.HasPrefix(lower, "ed2k:"):
+		if ed2kRegexp.MatchString(uri) {
+			return nil
+		}
+		return invalid
+	case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"), strings.HasPrefix(lower, "ftp://"):
+		u, err := url.Parse(uri)
+		if err != nil || u.Host == "" {
+			return invalid
+		}
+		return nil
+	}
+	return invalid
+}
+
+// isBtih checks info hash in hex or base32
+func isBtih(hash string) bool {
+	switch len(hash) {
+	case 40:
+		_, err := hex.DecodeString(hash)
+		return err == nil
+	case 32:
+		_, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash))
+		return err == nil
+	}
+	return false
+}
+
+type OfflineQuotaResp struct {
+	BasicResp
+	Count    StringInt64 `json:"count"`
+	Used     StringInt64 `json:"used"`
+	Surplus  StringInt64 `json:"surplus"`
+	Packages []struct {
+		Name    string      `json:"name"`
+		Count   StringInt64 `json:"count"`
+		Used    StringInt64 `json:"used"`
+		Surplus StringInt64 `json:"surplus"`
+	} `json:"package"`
+}
+
+type OfflineSpaceResp struct {
+	BasicResp
+	Size  string      `json:"size"`
+	Limit StringInt64 `json:"limit"`
+}
@@ -0,0 +1,230 @@
+package driver
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// next returns the next interval of backoff
+func (o *OfflinePollOptions) next(interval time.Duration) time.Duration {
+	if o.Factor > 1 {
+		interval = time.Duration(float64(interval) * o.Factor)
+	}
+	if o.MaxInterval > 0 && interval > o.MaxInterval {
+		interval = o.MaxInterval
+	}
+	return interval
+}
+
+// WaitOfflineTasks polls until all tasks of hashes are done or failed, returns final tasks in order of hashes.
+// A task which is removed while waiting is returned as nil. A task which has not shown up in the list yet is
+// waited for up to UnseenTimeout, then ErrNotExist is returned.
+func (c *Pan115Client) WaitOfflineTasks(ctx context.Context, hashes []string, opts ...OfflinePollOption) ([]*OfflineTask, error) {
+	if isCalledByAlistV3() {
+		return nil, ErrorNotSupportAlist
+	}
+	o := DefaultOfflinePollOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultOfflinePollOptions().Interval
+	}
+	results := make([]*OfflineTask, len(hashes))
+	seen := make([]bool, len(hashes))
+	start := time.Now()
+	interval := o.Interval
+	for {
+		tasks, err := c.ListAllOfflineTasks()
+		if err != nil {
+			return results, err
+		}
+		finished, unseen := collectOfflineTasks(hashes, offlineTaskMap(tasks), seen, results)
+		if len(unseen) > 0 && o.UnseenTimeout > 0 && time.Since(start) >= o.UnseenTimeout {
+			return results, errors.Wrapf(ErrNotExist, "offline tasks %s", strings.Join(unseen, ","))
+		}
+		if finished {
+			return results, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return results, ctx.Err()
+		case <-timer.C:
+		}
+		interval = o.next(interval)
+	}
+}
+
+// collectOfflineTasks fills results by hashes and marks seen tasks, returns whether all seen tasks are finished
+// and no task is unseen, and the hashes which have never been seen.
+func collectOfflineTasks(hashes []string, taskMap map[string]*OfflineTask, seen []bool, results []*OfflineTask) (bool, []string) {
+	finished := true
+	var unseen []string
+	for i, hash := range hashes {
+		task := taskMap[strings.ToLower(hash)]
+		results[i] = task
+		switch {
+		case task != nil:
+			seen[i] = true
+			if !task.IsDone() && !task.IsFailed() {
+				finished = false
+			}
+		case !seen[i]:
+			unseen = append(unseen, hash)
+			finished = false
+		}
+	}
+	return finished, unseen
+}
+
+type OfflineTaskEventType int
+
+const (
+	OfflineTaskEventAdded OfflineTaskEventType = iota
+	OfflineTaskEventProgress
+	OfflineTaskEventDone
+	OfflineTaskEventFailed
+	OfflineTaskEventRemoved
+	// OfflineTaskEventError is emitted when polling fails, watching goes on.
+	OfflineTaskEventError
+)
+
+func (t OfflineTaskEventType) String() string {
+	switch t {
+	case OfflineTaskEventAdded:
+		return "added"
+	case OfflineTaskEventProgress:
+		return "progress"
+	case OfflineTaskEventDone:
+		return "done"
+	case OfflineTaskEventFailed:
+		return "failed"
+	case OfflineTaskEventRemoved:
+		return "removed"
+	case OfflineTaskEventError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// OfflineTaskEvent describes a state change of an offline task.
+type OfflineTaskEvent struct {
+	Type OfflineTaskEventType
+	// Task is the latest state, or the last known state when removed.
+	Task *OfflineTask
+	Err  error
+}
+
+// WatchOfflineTasks polls tasks at an interval and emits state changes until ctx is done, then the channel is closed.
+// A task is reported as removed only after it is missing from two polls in a row.
+func (c *Pan115Client) WatchOfflineTasks(ctx context.Context, opts ...OfflinePollOption) <-chan OfflineTaskEvent {
+	o := DefaultOfflinePollOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultOfflinePollOptions().Interval
+	}
+	ch := make(chan OfflineTaskEvent, 16)
+	if isCalledByAlistV3() {
+		ch <- OfflineTaskEvent{Type: OfflineTaskEventError, Err: ErrorNotSupportAlist}
+		close(ch)
+		return ch
+	}
+	go func() {
+		defer close(ch)
+		emit := func(e OfflineTaskEvent) bool {
+			select {
+			case ch <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// prev stays nil until a poll succeeds, the first good poll is the baseline
+		var prev map[string]*OfflineTask
+		if o.EmitExisting {
+			prev = map[string]*OfflineTask{}
+		}
+		missing := map[string]bool{}
+		ticker := time.NewTicker(o.Interval)
+		defer ticker.Stop()
+		for first := true; ; first = false {
+			if !first {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+			tasks, err := c.ListAllOfflineTasks()
+			if err != nil {
+				if !emit(OfflineTaskEvent{Type: OfflineTaskEventError, Err: err}) {
+					return
+				}
+				continue
+			}
+			curr := offlineTaskMap(tasks)
+			if prev == nil {
+				prev = curr
+				continue
+			}
+			for _, e := range diffOfflineTasks(prev, curr, missing) {
+				if !emit(e) {
+					return
+				}
+			}
+			prev = curr
+		}
+	}()
+	return ch
+}
+
+func offlineTaskMap(tasks []*OfflineTask) map[string]*OfflineTask {
+	m := make(map[string]*OfflineTask, len(tasks))
+	for _, task := range tasks {
+		m[strings.ToLower(task.InfoHash)] = task
+	}
+	return m
+}
+
+// diffOfflineTasks compares two polls, a task missing for the first time is marked in missing and kept in curr,
+// it is reported as removed when it is still missing in the next poll.
+func diffOfflineTasks(prev, curr map[string]*OfflineTask, missing map[string]bool) []OfflineTaskEvent {
+	var events []OfflineTaskEvent
+	for hash, task := range curr {
+		delete(missing, hash)
+		old, ok := prev[hash]
+		if !ok {
+			events = append(events, OfflineTaskEvent{Type: OfflineTaskEventAdded, Task: task})
+		}
+		switch {
+		case task.IsDone() && (!ok || !old.IsDone()):
+			events = append(events, OfflineTaskEvent{Type: OfflineTaskEventDone, Task: task})
+		case task.IsFailed() && (!ok || !old.IsFailed()):
+			events = append(events, OfflineTaskEvent{Type: OfflineTaskEventFailed, Task: task})
+		case ok && (old.Status != task.Status || old.Percent != task.Percent):
+			events = append(events, OfflineTaskEvent{Type: OfflineTaskEventProgress, Task: task})
+		}
+	}
+	for hash, task := range prev {
+		if _, ok := curr[hash]; ok {
+			continue
+		}
+		if missing[hash] {
+			delete(missing, hash)
+			events = append(events, OfflineTaskEvent{Type: OfflineTaskEventRemoved, Task: task})
+			continue
+		}
+		missing[hash] = true
+		curr[hash] = task
+	}
+	return events
+}
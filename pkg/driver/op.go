@@ -164,7 +164,12 @@ func (c *Pan115Client) Stat(fileID string) (*FileStatInfo, error) {
 }
 
 // GetFile gets information of a file or directory by its ID.
-func (c *Pan115Client) GetFile(fileID string) (*File, error) {
+func (c *Pan115Client) GetFile(fileID string, opts ...ListOption) (*File, error) {
+	o := DefaultListOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	result := GetFileInfoResponse{}
 	req := c.NewRequest().
 		SetQueryParam("file_id", fileID).
@@ -180,5 +185,52 @@ func (c *Pan115Client) GetFile(fileID string) (*File, error) {
 	}
 	f := &File{}
 	f.from(fileInfo)
+	if o.Description {
+		if f.Description, err = c.GetDescription(f.FileID); err != nil {
+			return nil, err
+		}
+	}
 	return f, nil
 }
+
+// GetDescription get description of a file or directory
+func (c *Pan115Client) GetDescription(fileID string) (string, error) {
+	result := FileDescResp{}
+	req := c.NewRequest().
+		SetQueryParams(map[string]string{
+			"file_id":  fileID,
+			"format":   "json",
+			"compat":   "1",
+			"new_html": "1",
+		}).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Get(ApiFileDesc)
+	if err = CheckErr(err, &result, resp); err != nil {
+		return "", err
+	}
+	return result.Desc, nil
+}
+
+// SetDescription set description of a file or directory, empty text clears it
+func (c *Pan115Client) SetDescription(fileID, text string) error {
+	result := BasicResp{}
+	req := c.NewRequest().
+		SetFormData(map[string]string{
+			"fid":       fileID,
+			"file_desc": text,
+		}).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Post(ApiFileEdit)
+	return CheckErr(err, &result, resp)
+}
+
+func (c *Pan115Client) fillDescriptions(files []File) (err error) {
+	for i := range files {
+		if files[i].Description, err = c.GetDescription(files[i].FileID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
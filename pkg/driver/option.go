@@ -193,6 +193,13 @@ func UploadMultipartWithTokenRefreshTime(refreshTime time.Duration) UploadMultip
 
 type ListOptions struct {
 	ApiURLs []string
+	// Description fetches description of every file, costs one request per file
+	Description bool
+	// ExcludeHidden drops hidden files on client side. Hidden files are only returned by server in hidden mode,
+	// see EnterHiddenMode, so it has no effect out of hidden mode.
+	ExcludeHidden bool
+	// HiddenPassword enters hidden mode before listing and exits it afterwards, empty lists in current mode.
+	HiddenPassword string
 }
 
 func DefaultListOptions() *ListOptions {
@@ -211,6 +218,28 @@ func WithApiURLs(urls ...string) ListOption {
 	}
 }
 
+func WithDescription() ListOption {
+	return func(o *ListOptions) {
+		o.Description = true
+	}
+}
+
+// WithExcludeHidden drops hidden files from the list, which is only useful in hidden mode
+func WithExcludeHidden() ListOption {
+	return func(o *ListOptions) {
+		o.ExcludeHidden = true
+	}
+}
+
+// WithIncludeHidden lists hidden files too by entering hidden mode with the secret password for the call.
+// Hidden mode is account-wide, other sessions listing meanwhile see hidden files as well,
+// and hidden mode is exited afterwards even if it has been entered before.
+func WithIncludeHidden(password string) ListOption {
+	return func(o *ListOptions) {
+		o.HiddenPassword = password
+	}
+}
+
 func WithMultiUrls() ListOption {
 	return WithApiURLs([]string{
 		ApiFileList,
@@ -221,7 +250,9 @@ func WithMultiUrls() ListOption {
 }
 
 type OfflineOptions struct {
-	appVer string
+	appVer       string
+	torrentDirID string
+	preflight    bool
 }
 
 func DefaultOfflineOptions() OfflineOptions {
@@ -237,3 +268,227 @@ func WithAppVer(appVer string) OfflineOption {
 		o.appVer = appVer
 	}
 }
+
+// WithPreflight validates links locally and checks quota and free space before adding tasks
+func WithPreflight() OfflineOption {
+	return func(o *OfflineOptions) {
+		o.preflight = true
+	}
+}
+
+// WithTorrentDirID sets the directory which .torrent files are uploaded into
+func WithTorrentDirID(dirID string) OfflineOption {
+	return func(o *OfflineOptions) {
+		o.torrentDirID = dirID
+	}
+}
+
+type BatchOptions struct {
+	// ChunkSize is the max number of ids sent in one request.
+	ChunkSize int
+	// Concurrency is the max number of chunks in flight.
+	Concurrency int
+	// IsolateFailures retries each id of a failed chunk one by one,
+	// so that the failed ids can be told apart from the good ones.
+	IsolateFailures bool
+}
+
+func DefaultBatchOptions() *BatchOptions {
+	return &BatchOptions{
+		ChunkSize:       1000,
+		Concurrency:     2,
+		IsolateFailures: true,
+	}
+}
+
+type BatchOption func(o *BatchOptions)
+
+func BatchWithChunkSize(n int) BatchOption {
+	return func(o *BatchOptions) {
+		if n > 0 {
+			o.ChunkSize = n
+		}
+	}
+}
+
+func BatchWithConcurrency(n int) BatchOption {
+	return func(o *BatchOptions) {
+		if n > 0 {
+			o.Concurrency = n
+		}
+	}
+}
+
+func BatchWithIsolateFailures(e bool) BatchOption {
+	return func(o *BatchOptions) {
+		o.IsolateFailures = e
+	}
+}
+
+type OfflinePollOptions struct {
+	// Interval is the first interval between polls, a non-positive one falls back to the default.
+	Interval time.Duration
+	// MaxInterval caps the interval which grows by Factor after every poll.
+	MaxInterval time.Duration
+	Factor      float64
+	// EmitExisting emits events for tasks which exist before watching.
+	EmitExisting bool
+	// UnseenTimeout is how long to wait for a task to show up in the list, zero means no limit.
+	UnseenTimeout time.Duration
+}
+
+func DefaultOfflinePollOptions() *OfflinePollOptions {
+	return &OfflinePollOptions{
+		Interval:      time.Second * 3,
+		MaxInterval:   time.Minute,
+		Factor:        1.5,
+		UnseenTimeout: time.Minute,
+	}
+}
+
+type OfflinePollOption func(o *OfflinePollOptions)
+
+func OfflinePollWithInterval(interval time.Duration) OfflinePollOption {
+	return func(o *OfflinePollOptions) {
+		o.Interval = interval
+	}
+}
+
+func OfflinePollWithMaxInterval(interval time.Duration) OfflinePollOption {
+	return func(o *OfflinePollOptions) {
+		o.MaxInterval = interval
+	}
+}
+
+func OfflinePollWithFactor(factor float64) OfflinePollOption {
+	return func(o *OfflinePollOptions) {
+		o.Factor = factor
+	}
+}
+
+func OfflinePollWithExisting(e bool) OfflinePollOption {
+	return func(o *OfflinePollOptions) {
+		o.EmitExisting = e
+	}
+}
+
+func OfflinePollWithUnseenTimeout(timeout time.Duration) OfflinePollOption {
+	return func(o *OfflinePollOptions) {
+		o.UnseenTimeout = timeout
+	}
+}
+
+type QRCodeLoginOptions struct {
+	// Interval is the first interval between status polls, which grows up to MaxInterval while waiting.
+	Interval    time.Duration
+	MaxInterval time.Duration
+	// MaxRegenerate is the max times to regenerate QRCode when it expires, negative means unlimited.
+	MaxRegenerate int
+	// OnStatus is called with every polled status, optional.
+	OnStatus func(status *QRCodeStatus)
+	// SlotChecker is a logged in client of the account, which checks the slot of app before login, optional.
+	SlotChecker *Pan115Client
+}
+
+func DefaultQRCodeLoginOptions() *QRCodeLoginOptions {
+	return &QRCodeLoginOptions{
+		Interval:      time.Second,
+		MaxInterval:   time.Second * 5,
+		MaxRegenerate: 3,
+	}
+}
+
+type QRCodeLoginOption func(o *QRCodeLoginOptions)
+
+func QRCodeLoginWithInterval(interval, maxInterval time.Duration) QRCodeLoginOption {
+	return func(o *QRCodeLoginOptions) {
+		o.Interval = interval
+		o.MaxInterval = maxInterval
+	}
+}
+
+func QRCodeLoginWithMaxRegenerate(n int) QRCodeLoginOption {
+	return func(o *QRCodeLoginOptions) {
+		o.MaxRegenerate = n
+	}
+}
+
+func QRCodeLoginWithOnStatus(onStatus func(status *QRCodeStatus)) QRCodeLoginOption {
+	return func(o *QRCodeLoginOptions) {
+		o.OnStatus = onStatus
+	}
+}
+
+// QRCodeLoginWithSlotCheck fails the login with ErrLoginAppSlotInUse when it would kick out a device of the account of checker.
+func QRCodeLoginWithSlotCheck(checker *Pan115Client) QRCodeLoginOption {
+	return func(o *QRCodeLoginOptions) {
+		o.SlotChecker = checker
+	}
+}
+
+type SessionMonitorOptions struct {
+	// Interval is the interval between cookie checks, a non-positive one falls back to the default.
+	Interval time.Duration
+	// FailureThreshold is the number of consecutive failed checks before the session is reported invalid.
+	FailureThreshold int
+	// OnInvalid is called when the session turns invalid, optional.
+	OnInvalid func(err error)
+	// OnRecovered is called when an invalid session turns valid again, e.g. after a re-login, optional.
+	OnRecovered func()
+}
+
+func DefaultSessionMonitorOptions() *SessionMonitorOptions {
+	return &SessionMonitorOptions{
+		Interval:         time.Minute * 5,
+		FailureThreshold: 2,
+	}
+}
+
+type SessionMonitorOption func(o *SessionMonitorOptions)
+
+func SessionMonitorWithInterval(interval time.Duration) SessionMonitorOption {
+	return func(o *SessionMonitorOptions) {
+		o.Interval = interval
+	}
+}
+
+func SessionMonitorWithFailureThreshold(n int) SessionMonitorOption {
+	return func(o *SessionMonitorOptions) {
+		o.FailureThreshold = n
+	}
+}
+
+func SessionMonitorWithOnInvalid(onInvalid func(err error)) SessionMonitorOption {
+	return func(o *SessionMonitorOptions) {
+		o.OnInvalid = onInvalid
+	}
+}
+
+func SessionMonitorWithOnRecovered(onRecovered func()) SessionMonitorOption {
+	return func(o *SessionMonitorOptions) {
+		o.OnRecovered = onRecovered
+	}
+}
+
+type AccountPoolOptions struct {
+	// Cooldown is how long an account is skipped after a failure, it doubles with consecutive failures.
+	Cooldown time.Duration
+	// MaxCooldown caps the cooldown.
+	MaxCooldown time.Duration
+}
+
+func DefaultAccountPoolOptions() *AccountPoolOptions {
+	return &AccountPoolOptions{
+		Cooldown:    time.Minute,
+		MaxCooldown: time.Minute * 30,
+	}
+}
+
+type AccountPoolOption func(o *AccountPoolOptions)
+
+func AccountPoolWithCooldown(cooldown, maxCooldown time.Duration) AccountPoolOption {
+	return func(o *AccountPoolOptions) {
+		o.Cooldown = cooldown
+		o.MaxCooldown = maxCooldown
+	}
+}
@@ -22,6 +22,19 @@ func (s *QRCodeSession) QRCode() ([]byte, error) {
 	return qrcode.Encode(s.QrcodeContent, qrcode.Medium, 256)
 }
 
+// QRCodeTerminal renders QRCode for terminal, small uses half blocks which takes half of lines,
+// inverse swaps colors for terminals with light background.
+func (s *QRCodeSession) QRCodeTerminal(small, inverse bool) (string, error) {
+	q, err := qrcode.New(s.QrcodeContent, qrcode.Medium)
+	if err != nil {
+		return "", err
+	}
+	if small {
+		return q.ToSmallString(inverse), nil
+	}
+	return q.ToString(inverse), nil
+}
+
 // QRCodeByApi get QRCode matrix or image by api.
 func (s *QRCodeSession) QRCodeByApi() ([]byte, error) {
 	resp, err := resty.New().R().Get(fmt.Sprintf(ApiQrcodeImage, s.UID))
@@ -79,7 +92,9 @@ func (c *Pan115Client) QRCodeLoginWithApp(s *QRCodeSession, app LoginApp) (*Cred
 		return nil, err
 	}
 
-	return &result.Data.Credential, nil
+	cr := result.Data.Credential
+	cr.App = app
+	return &cr, nil
 }
 
 type QRCodeStatus struct {
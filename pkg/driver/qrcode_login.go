@@ -0,0 +1,86 @@
+package driver
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LoginByQRCode logins through QRCode with app end to end: starts a session, calls onQRCode to show the QRCode,
+// polls status until it is allowed and imports the credential. onQRCode must not be nil, it is called again when
+// the QRCode expires and is regenerated.
+// The app kicks out the device logged in with the same app, see PickLoginApp and QRCodeLoginWithSlotCheck.
+func (c *Pan115Client) LoginByQRCode(ctx context.Context, app LoginApp, onQRCode func(s *QRCodeSession), opts ...QRCodeLoginOption) (*Credential, error) {
+	if onQRCode == nil {
+		return nil, errors.Wrap(ErrWrongParams, "onQRCode is required to show the qrcode")
+	}
+	o := DefaultQRCodeLoginOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.SlotChecker != nil {
+		if err := o.SlotChecker.CheckLoginAppSlot(app); err != nil {
+			return nil, err
+		}
+	}
+
+	for regenerated := 0; ; regenerated++ {
+		s, err := c.QRCodeStart()
+		if err != nil {
+			return nil, err
+		}
+		onQRCode(s)
+
+		cr, err := c.waitQRCode(ctx, s, app, o)
+		if !errors.Is(err, ErrQrcodeExpired) {
+			return cr, err
+		}
+		if o.MaxRegenerate >= 0 && regenerated >= o.MaxRegenerate {
+			return nil, err
+		}
+	}
+}
+
+func (c *Pan115Client) waitQRCode(ctx context.Context, s *QRCodeSession, app LoginApp, o *QRCodeLoginOptions) (*Credential, error) {
+	interval := o.Interval
+	for {
+		status, err := c.QRCodeStatus(s)
+		if err != nil {
+			return nil, err
+		}
+		if o.OnStatus != nil {
+			o.OnStatus(status)
+		}
+
+		switch {
+		case status.IsAllowed():
+			cr, err := c.QRCodeLoginWithApp(s, app)
+			if err != nil {
+				return nil, err
+			}
+			c.ImportCredential(cr)
+			return cr, nil
+		case status.IsExpired():
+			return nil, ErrQrcodeExpired
+		case status.IsCanceled():
+			return nil, ErrQrcodeCanceled
+		case status.IsScanned():
+			// confirmation is coming soon
+			interval = o.Interval
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		if status.IsWaiting() {
+			if interval *= 2; interval > o.MaxInterval {
+				interval = o.MaxInterval
+			}
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package driver
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DeletedAt returns the time when the item is deleted
+func (item *RecycleBinItem) DeletedAt() time.Time {
+	return time.Unix(int64(item.DeleteTime), 0)
+}
+
+// RecycleBinFilter returns true if the item should be kept
+type RecycleBinFilter func(item *RecycleBinItem) bool
+
+// RecycleBinNameContains keeps items whose name contains substr, case insensitive
+func RecycleBinNameContains(substr string) RecycleBinFilter {
+	substr = strings.ToLower(substr)
+	return func(item *RecycleBinItem) bool {
+		return strings.Contains(strings.ToLower(item.FileName), substr)
+	}
+}
+
+// RecycleBinInDir keeps items deleted from directory
+func RecycleBinInDir(parentID string) RecycleBinFilter {
+	return func(item *RecycleBinItem) bool {
+		return string(item.ParentId) == parentID
+	}
+}
+
+// RecycleBinDeletedBetween keeps items deleted in [from, to), zero time means unbounded
+func RecycleBinDeletedBetween(from, to time.Time) RecycleBinFilter {
+	return func(item *RecycleBinItem) bool {
+		t := item.DeletedAt()
+		return (from.IsZero() || !t.Before(from)) && (to.IsZero() || t.Before(to))
+	}
+}
+
+func matchRecycleBinItem(item *RecycleBinItem, filters []RecycleBinFilter) bool {
+	for _, filter := range filters {
+		if !filter(item) {
+			return false
+		}
+	}
+	return true
+}
+
+const RecycleBinListLimit = 1000
+
+// RecycleBinIterator iterates items in recycle bin across pages.
+type RecycleBinIterator struct {
+	client  *Pan115Client
+	filters []RecycleBinFilter
+
+	offset int
+	done   bool
+	items  []RecycleBinItem
+	item   *RecycleBinItem
+	err    error
+}
+
+// RecycleBinItems returns an iterator of items in recycle bin which match all filters
+func (c *Pan115Client) RecycleBinItems(filters ...RecycleBinFilter) *RecycleBinIterator {
+	return &RecycleBinIterator{
+		client:  c,
+		filters: filters,
+	}
+}
+
+// Next advances to the next item, returns false when there is no more item or an error occurs
+func (it *RecycleBinIterator) Next() bool {
+	for it.err == nil {
+		for len(it.items) > 0 {
+			item := it.items[0]
+			it.items = it.items[1:]
+			if matchRecycleBinItem(&item, it.filters) {
+				it.item = &item
+				return true
+			}
+		}
+		if it.done {
+			break
+		}
+		page, err := it.client.listRecycleBin(it.offset, RecycleBinListLimit)
+		if err != nil {
+			it.err = err
+			break
+		}
+		// server may cap the limit and clamps an offset out of range, so stop by count
+		it.offset += len(page.Data)
+		it.done = len(page.Data) == 0 || it.offset >= int(page.Count)
+		it.items = page.Data
+	}
+	it.item = nil
+	return false
+}
+
+// Item returns the current item
+func (it *RecycleBinIterator) Item() *RecycleBinItem {
+	return it.item
+}
+
+// Err returns the error occurred in iteration
+func (it *RecycleBinIterator) Err() error {
+	return it.err
+}
+
+// ListAllRecycleBin list items of all pages in recycle bin which match all filters
+func (c *Pan115Client) ListAllRecycleBin(filters ...RecycleBinFilter) ([]RecycleBinItem, error) {
+	var items []RecycleBinItem
+	it := c.RecycleBinItems(filters...)
+	for it.Next() {
+		items = append(items, *it.Item())
+	}
+	return items, it.Err()
+}
+
+// RestoreRecycleBinItems revert items, the original parent directory is recreated by ParentName
+// under root directory if it does not exist anymore. 115 reverts such items into root directory,
+// they are found there by name and size and moved into the recreated directory.
+func (c *Pan115Client) RestoreRecycleBinItems(items ...RecycleBinItem) error {
+	if isCalledByAlistV3() {
+		return ErrorNotSupportAlist
+	}
+	groups := map[string][]RecycleBinItem{}
+	var parents []string
+	for _, item := range items {
+		parentID := string(item.ParentId)
+		if _, ok := groups[parentID]; !ok {
+			parents = append(parents, parentID)
+		}
+		groups[parentID] = append(groups[parentID], item)
+	}
+
+	for _, parentID := range parents {
+		group := groups[parentID]
+		rIDs := make([]string, len(group))
+		for i, item := range group {
+			rIDs[i] = item.FileId
+		}
+		exists := parentID == "" || parentID == "0"
+		if !exists {
+			var err error
+			if exists, err = c.dirExists(parentID); err != nil {
+				return err
+			}
+		}
+		if exists {
+			if err := c.RevertRecycleBin(rIDs...); err != nil {
+				return err
+			}
+			continue
+		}
+
+		dirID, err := c.mkdirOrGet("0", group[0].ParentName)
+		if err != nil {
+			return err
+		}
+		before, err := c.List("0")
+		if err != nil {
+			return err
+		}
+		if err := c.RevertRecycleBin(rIDs...); err != nil {
+			return err
+		}
+		after, err := c.List("0")
+		if err != nil {
+			return err
+		}
+		existed := make(map[string]struct{}, len(*before))
+		for _, f := range *before {
+			existed[f.FileID] = struct{}{}
+		}
+		var reverted []File
+		for _, f := range *after {
+			if _, ok := existed[f.FileID]; !ok {
+				reverted = append(reverted, f)
+			}
+		}
+
+		fileIDs, missing := matchRevertedFiles(group, reverted)
+		if len(fileIDs) > 0 {
+			if err := c.Move(dirID, fileIDs...); err != nil {
+				return err
+			}
+		}
+		if len(missing) > 0 {
+			return errors.Wrapf(ErrNotExist, "reverted items not found in root directory: %s", strings.Join(missing, ","))
+		}
+	}
+	return nil
+}
+
+// matchRevertedFiles returns ids of files which match items by name and size, and names of items not found.
+func matchRevertedFiles(items []RecycleBinItem, files []File) ([]string, []string) {
+	used := make([]bool, len(files))
+	var (
+		fileIDs []string
+		missing []string
+	)
+	for _, item := range items {
+		found := false
+		for i, f := range files {
+			if used[i] || f.Name != item.FileName || (!f.IsDirectory && f.Size != int64(item.FileSize)) {
+				continue
+			}
+			used[i] = true
+			fileIDs = append(fileIDs, f.FileID)
+			found = true
+			break
+		}
+		if !found {
+			missing = append(missing, item.FileName)
+		}
+	}
+	return fileIDs, missing
+}
+
+// dirExists tells whether dirID is an existing directory, errors other than a missing target are returned
+func (c *Pan115Client) dirExists(dirID string) (bool, error) {
+	f, err := c.GetFile(dirID)
+	if errors.Is(err, ErrNotExist) || errors.Is(err, ErrDownloadFileNotExistOrHasDeleted) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return f.IsDirectory && f.FileID == dirID, nil
+}
+
+// mkdirOrGet make a directory, returns id of the existed one with the same name
+func (c *Pan115Client) mkdirOrGet(parentID, name string) (string, error) {
+	dirID, err := c.Mkdir(parentID, name)
+	if err == nil {
+		return dirID, nil
+	}
+	if !errors.Is(err, ErrExist) {
+		return "", err
+	}
+	files, err := c.List(parentID)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range *files {
+		if f.IsDirectory && f.Name == name {
+			return f.FileID, nil
+		}
+	}
+	return "", errors.Wrap(ErrNotExist, name)
+}
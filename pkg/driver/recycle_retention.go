@@ -0,0 +1,116 @@
+package driver
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RecycleRetentionPolicy describes which items in recycle bin should be purged.
+type RecycleRetentionPolicy struct {
+	// MaxAge purges items deleted more than MaxAge ago, 0 disables.
+	MaxAge time.Duration
+	// MaxSize purges items larger than MaxSize bytes, 0 disables.
+	MaxSize int64
+	// DryRun only reports items which would be purged.
+	DryRun bool
+	// Password is the password of recycle bin, required unless DryRun.
+	Password SecretSource
+	// Log receives a JSON line for every purged item, optional.
+	Log io.Writer
+}
+
+const (
+	RetentionReasonAge  = "age"
+	RetentionReasonSize = "size"
+)
+
+// RetentionRecord describes a purged item
+type RetentionRecord struct {
+	FileID    string    `json:"file_id"`
+	FileName  string    `json:"file_name"`
+	FileSize  int64     `json:"file_size"`
+	ParentID  string    `json:"parent_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgedAt  time.Time `json:"purged_at"`
+	Reason    string    `json:"reason"`
+	DryRun    bool      `json:"dry_run"`
+}
+
+func (p *RecycleRetentionPolicy) reason(item *RecycleBinItem, now time.Time) string {
+	if p.MaxAge > 0 && now.Sub(item.DeletedAt()) > p.MaxAge {
+		return RetentionReasonAge
+	}
+	if p.MaxSize > 0 && int64(item.FileSize) > p.MaxSize {
+		return RetentionReasonSize
+	}
+	return ""
+}
+
+// ApplyRecycleRetention purges items in recycle bin by policy, returns records of purged items
+func (c *Pan115Client) ApplyRecycleRetention(policy *RecycleRetentionPolicy) ([]*RetentionRecord, error) {
+	now := time.Now()
+	var (
+		records []*RetentionRecord
+		byID    = map[string]*RetentionRecord{}
+		ids     []string
+	)
+	it := c.RecycleBinItems()
+	for it.Next() {
+		item := it.Item()
+		reason := policy.reason(item, now)
+		if reason == "" {
+			continue
+		}
+		record := &RetentionRecord{
+			FileID:    item.FileId,
+			FileName:  item.FileName,
+			FileSize:  int64(item.FileSize),
+			ParentID:  string(item.ParentId),
+			DeletedAt: item.DeletedAt(),
+			Reason:    reason,
+			DryRun:    policy.DryRun,
+		}
+		byID[item.FileId] = record
+		ids = append(ids, item.FileId)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if policy.DryRun {
+		for _, id := range ids {
+			records = append(records, byID[id])
+		}
+	} else if len(ids) > 0 {
+		if policy.Password == nil {
+			return nil, errors.Wrap(ErrWrongParams, "password of recycle bin is required")
+		}
+		var password string
+		if password, err = policy.Password.Secret(); err != nil {
+			return nil, err
+		}
+		result := runBatch(ids, func(ids []string) error {
+			return c.CleanRecycleBin(password, ids...)
+		}, BatchWithIsolateFailures(false))
+		purgedAt := time.Now()
+		for _, id := range result.Succeeded {
+			byID[id].PurgedAt = purgedAt
+			records = append(records, byID[id])
+		}
+		err = result.Err()
+	}
+
+	if policy.Log != nil {
+		enc := json.NewEncoder(policy.Log)
+		for _, record := range records {
+			if err := enc.Encode(record); err != nil {
+				return records, err
+			}
+		}
+	}
+	return records, err
+}
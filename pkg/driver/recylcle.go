@@ -25,6 +25,14 @@ func (c *Pan115Client) CleanRecycleBin(password string, rIDs ...string) error {
 
 // ListRecycleBin list the recycle bin
 func (c *Pan115Client) ListRecycleBin(offset, limit int) ([]RecycleBinItem, error) {
+	result, err := c.listRecycleBin(offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+func (c *Pan115Client) listRecycleBin(offset, limit int) (*RecycleListResponse, error) {
 	result := RecycleListResponse{}
 	req := c.NewRequest().
 		SetQueryParams(map[string]string{
@@ -42,12 +50,14 @@ func (c *Pan115Client) ListRecycleBin(offset, limit int) ([]RecycleBinItem, erro
 	if err != nil {
 		return nil, err
 	}
-	return result.Data, nil
+	return &result, nil
 }
 
 type RecycleListResponse struct {
 	BasicResp
 	Data []RecycleBinItem `json:"data"`
+	// Count is the number of all items in recycle bin
+	Count StringInt `json:"count"`
 }
 
 type RecycleBinItem struct {
@@ -0,0 +1,158 @@
+package driver
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// BatchRename rename files or directories with a map of file id to new name
+func (c *Pan115Client) BatchRename(names map[string]string, opts ...BatchOption) *BatchResult {
+	fileIDs := make([]string, 0, len(names))
+	for fileID := range names {
+		fileIDs = append(fileIDs, fileID)
+	}
+	sort.Strings(fileIDs)
+	if isCalledByAlistV3() {
+		return failBatch(fileIDs, ErrorNotSupportAlist)
+	}
+	return runBatch(fileIDs, func(ids []string) error {
+		return c.batchRename(ids, names)
+	}, opts...)
+}
+
+func (c *Pan115Client) batchRename(fileIDs []string, names map[string]string) error {
+	if len(fileIDs) == 0 {
+		return nil
+	}
+	form := map[string]string{}
+	for _, fileID := range fileIDs {
+		form[fmt.Sprintf("files_new_name[%s]", fileID)] = names[fileID]
+	}
+
+	result := BasicResp{}
+	req := c.NewRequest().
+		SetFormData(form).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Post(ApiFileRename)
+	return CheckErr(err, &result, resp)
+}
+
+// RenameRule returns a new name from the old name, index is the position of the file in the batch.
+type RenameRule func(index int, name string) string
+
+// RenameRegexp replaces the matches of re with repl, see regexp.ReplaceAllString
+func RenameRegexp(re *regexp.Regexp, repl string) RenameRule {
+	return func(_ int, name string) string {
+		return re.ReplaceAllString(name, repl)
+	}
+}
+
+// RenameTemplate renders template with placeholders:
+//   - {name}: base name without extension
+//   - {ext}: extension with leading dot
+//   - {n}: sequence number, starts from start and is padded with zeros to width
+func RenameTemplate(template string, start, width int) RenameRule {
+	return func(index int, name string) string {
+		ext := path.Ext(name)
+		n := strconv.Itoa(start + index)
+		if len(n) < width {
+			n = strings.Repeat("0", width-len(n)) + n
+		}
+		return strings.NewReplacer(
+			"{name}", strings.TrimSuffix(name, ext),
+			"{ext}", ext,
+			"{n}", n,
+		).Replace(template)
+	}
+}
+
+// RenameExtension changes extension of the name, ext may be with or without leading dot, empty ext removes extension
+func RenameExtension(ext string) RenameRule {
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return func(_ int, name string) string {
+		return strings.TrimSuffix(name, path.Ext(name)) + ext
+	}
+}
+
+type RenameCase int
+
+const (
+	RenameCaseLower RenameCase = iota
+	RenameCaseUpper
+	RenameCaseTitle
+)
+
+// RenameToCase normalizes case of the name
+func RenameToCase(mode RenameCase) RenameRule {
+	return func(_ int, name string) string {
+		switch mode {
+		case RenameCaseLower:
+			return strings.ToLower(name)
+		case RenameCaseUpper:
+			return strings.ToUpper(name)
+		case RenameCaseTitle:
+			// capitalises the first letter after whitespace in place, so whitespace is kept as it is
+			var b strings.Builder
+			b.Grow(len(name))
+			start := true
+			for _, r := range strings.ToLower(name) {
+				if start {
+					r = unicode.ToTitle(r)
+				}
+				start = unicode.IsSpace(r)
+				b.WriteRune(r)
+			}
+			return b.String()
+		}
+		return name
+	}
+}
+
+// RenamePreview describes a rename which will be applied.
+type RenamePreview struct {
+	FileID  string
+	OldName string
+	NewName string
+}
+
+func (p RenamePreview) String() string {
+	return fmt.Sprintf("%s: %s -> %s", p.FileID, p.OldName, p.NewName)
+}
+
+// PreviewRename applies rules in order to files, files whose name is unchanged are skipped
+func PreviewRename(files []File, rules ...RenameRule) []RenamePreview {
+	previews := make([]RenamePreview, 0, len(files))
+	for i, f := range files {
+		name := f.Name
+		for _, rule := range rules {
+			name = rule(i, name)
+		}
+		if name == "" || name == f.Name {
+			continue
+		}
+		previews = append(previews, RenamePreview{
+			FileID:  f.FileID,
+			OldName: f.Name,
+			NewName: name,
+		})
+	}
+	return previews
+}
+
+// RenameByRules rename files by rules, returns previews of applied renames
+func (c *Pan115Client) RenameByRules(files []File, rules []RenameRule, opts ...BatchOption) ([]RenamePreview, *BatchResult) {
+	previews := PreviewRename(files, rules...)
+	names := make(map[string]string, len(previews))
+	for _, p := range previews {
+		names[p.FileID] = p.NewName
+	}
+	return previews, c.BatchRename(names, opts...)
+}
@@ -99,8 +99,9 @@ type FileInfo struct {
 	Sha1     string      `json:"sha"`
 	PickCode string      `json:"pc"`
 
-	IsStar StringInt    `json:"m"`
-	Labels []*LabelInfo `json:"fl"`
+	IsStar   StringInt    `json:"m"`
+	IsHidden StringInt    `json:"hdf"`
+	Labels   []*LabelInfo `json:"fl"`
 
 	CreateTime StringInt64 `json:"tp"`
 	UpdateTime string      `json:"t"`
@@ -282,6 +283,11 @@ func (r *FileStatResponse) Err(respBody ...string) error {
 	return nil
 }
 
+type FileDescResp struct {
+	BasicResp
+	Desc string `json:"desc"`
+}
+
 type GetFileInfoResponse struct {
 	BasicResp
 	Files []*FileInfo `json:"data"`
@@ -348,23 +354,7 @@ type ShareSnapResp struct {
 			UserName string `json:"user_name"`
 			Face     string `json:"face"`
 		} `json:"userinfo"`
-		Shareinfo struct {
-			SnapID           string      `json:"snap_id"`
-			FileSize         StringInt64 `json:"file_size"`
-			ShareTitle       string      `json:"share_title"`
-			ShareState       StringInt64 `json:"share_state"`
-			ForbidReason     string      `json:"forbid_reason"`
-			CreateTime       StringInt64 `json:"create_time"`
-			ReceiveCode      string      `json:"receive_code"`
-			ReceiveCount     StringInt64 `json:"receive_count"`
-			ExpireTime       int64       `json:"expire_time"`
-			FileCategory     int64       `json:"file_category"`
-			AutoRenewal      StringInt64 `json:"auto_renewal"`
-			AutoFillRecvcode StringInt64 `json:"auto_fill_recvcode"`
-			CanReport        int         `json:"can_report"`
-			CanNotice        int         `json:"can_notice"`
-			HaveVioFile      int         `json:"have_vio_file"`
-		} `json:"shareinfo"`
+		Shareinfo  ShareInfo   `json:"shareinfo"`
 		Count      int         `json:"count"`
 		List       []ShareFile `json:"list"`
 		ShareState StringInt64 `json:"share_state"`
@@ -377,6 +367,29 @@ type ShareSnapResp struct {
 	} `json:"data"`
 }
 
+type ShareInfo struct {
+	SnapID           string      `json:"snap_id"`
+	FileSize         StringInt64 `json:"file_size"`
+	ShareTitle       string      `json:"share_title"`
+	ShareState       StringInt64 `json:"share_state"`
+	ForbidReason     string      `json:"forbid_reason"`
+	CreateTime       StringInt64 `json:"create_time"`
+	ReceiveCode      string      `json:"receive_code"`
+	ReceiveCount     StringInt64 `json:"receive_count"`
+	ExpireTime       int64       `json:"expire_time"`
+	FileCategory     int64       `json:"file_category"`
+	AutoRenewal      StringInt64 `json:"auto_renewal"`
+	AutoFillRecvcode StringInt64 `json:"auto_fill_recvcode"`
+	CanReport        int         `json:"can_report"`
+	CanNotice        int         `json:"can_notice"`
+	HaveVioFile      int         `json:"have_vio_file"`
+
+	// Only returned to the owner of the share
+	ShareCode     string      `json:"share_code,omitempty"`
+	ShareUrl      string      `json:"share_url,omitempty"`
+	ShareDuration StringInt64 `json:"share_duration,omitempty"`
+}
+
 type ShareFile struct {
 	FileID     string       `json:"fid"`
 	UID        int          `json:"uid"`
@@ -396,6 +409,19 @@ type ShareFile struct {
 	// U          string       `json:"u"`
 }
 
+// IsDirectory returns true if the shared file is a directory
+func (f *ShareFile) IsDirectory() bool {
+	return f.IsFile == 0
+}
+
+// ID returns file id of a file or category id of a directory
+func (f *ShareFile) ID() string {
+	if f.IsDirectory() {
+		return string(f.CategoryID)
+	}
+	return f.FileID
+}
+
 type UploadResult struct {
 	BasicResp
 	Data struct {
@@ -415,3 +441,20 @@ type APIGetDirIDResp struct {
 	CategoryID IntString `json:"id"`
 	IsPrivate  IntString `json:"is_private"`
 }
+
+type LoginDevicesResp struct {
+	State   int              `json:"state"`
+	Code    int              `json:"code"`
+	Message string           `json:"message"`
+	Data    LoginDevicesInfo `json:"data"`
+}
+
+func (resp *LoginDevicesResp) Err(respBody ...string) error {
+	if resp.State == 1 {
+		return nil
+	}
+	if len(respBody) > 0 {
+		return GetErr(resp.Code, respBody[0])
+	}
+	return GetErr(resp.Code)
+}
@@ -0,0 +1,42 @@
+package driver
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SecretSource provides a secret such as a password, so that it is not written in code.
+type SecretSource interface {
+	Secret() (string, error)
+}
+
+// SecretFunc adapts a function to SecretSource
+type SecretFunc func() (string, error)
+
+func (f SecretFunc) Secret() (string, error) {
+	return f()
+}
+
+// EnvSecret reads secret from environment variable
+func EnvSecret(name string) SecretSource {
+	return SecretFunc(func() (string, error) {
+		secret, ok := os.LookupEnv(name)
+		if !ok || secret == "" {
+			return "", errors.Wrap(ErrNotExist, "env "+name)
+		}
+		return secret, nil
+	})
+}
+
+// FileSecret reads secret from file, surrounding spaces are trimmed
+func FileSecret(name string) SecretSource {
+	return SecretFunc(func() (string, error) {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	})
+}
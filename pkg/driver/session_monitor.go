@@ -0,0 +1,112 @@
+package driver
+
+import (
+	"context"
+	"time"
+)
+
+type SessionEventType int
+
+const (
+	// SessionEventInvalid is emitted when the cookie is logged out, e.g. kicked by another device of the same app.
+	SessionEventInvalid SessionEventType = iota
+	// SessionEventRecovered is emitted when an invalid session turns valid again.
+	SessionEventRecovered
+	// SessionEventError is emitted when the check request fails, it does not count as invalid.
+	SessionEventError
+)
+
+func (t SessionEventType) String() string {
+	switch t {
+	case SessionEventInvalid:
+		return "invalid"
+	case SessionEventRecovered:
+		return "recovered"
+	case SessionEventError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// SessionEvent describes a health change of the session.
+type SessionEvent struct {
+	Type SessionEventType
+	// UserID is the user of the credential being checked.
+	UserID int64
+	Err    error
+	Time   time.Time
+}
+
+// sessionHealth turns check results into events.
+type sessionHealth struct {
+	threshold int
+	failures  int
+	invalid   bool
+}
+
+func (h *sessionHealth) update(valid bool, err error) *SessionEvent {
+	switch {
+	case err != nil:
+		return &SessionEvent{Type: SessionEventError, Err: err}
+	case valid:
+		h.failures = 0
+		if h.invalid {
+			h.invalid = false
+			return &SessionEvent{Type: SessionEventRecovered}
+		}
+	default:
+		h.failures++
+		if !h.invalid && h.failures >= h.threshold {
+			h.invalid = true
+			return &SessionEvent{Type: SessionEventInvalid, Err: ErrBadCookie}
+		}
+	}
+	return nil
+}
+
+// MonitorSession checks the cookie with CookieCheck at an interval, which does not logout of other devices,
+// and emits health changes until ctx is done, then the channel is closed.
+// Monitoring goes on after the session turns invalid, so a re-login with ImportCredential is reported as recovered.
+// Callbacks in opts run before the event is sent, the channel must be drained either way.
+func (c *Pan115Client) MonitorSession(ctx context.Context, opts ...SessionMonitorOption) <-chan SessionEvent {
+	o := DefaultSessionMonitorOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.FailureThreshold < 1 {
+		o.FailureThreshold = 1
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultSessionMonitorOptions().Interval
+	}
+	ch := make(chan SessionEvent, 4)
+	go func() {
+		defer close(ch)
+		health := &sessionHealth{threshold: o.FailureThreshold}
+		ticker := time.NewTicker(o.Interval)
+		defer ticker.Stop()
+		for {
+			if e := health.update(c.cookieCheck()); e != nil {
+				e.UserID = c.UserID
+				e.Time = time.Now()
+				switch {
+				case e.Type == SessionEventInvalid && o.OnInvalid != nil:
+					o.OnInvalid(e.Err)
+				case e.Type == SessionEventRecovered && o.OnRecovered != nil:
+					o.OnRecovered()
+				}
+				select {
+				case ch <- *e:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return ch
+}
@@ -2,6 +2,9 @@ package driver
 
 import (
 	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 type Query func(query *map[string]string)
@@ -49,3 +52,96 @@ func (c *Pan115Client) GetShareSnap(shareCode, receiveCode, dirID string, Querie
 
 	return &result, nil
 }
+
+const ShareSnapLimit = 1000
+
+// ListShareFiles list all files and directories of a shared directory
+func (c *Pan115Client) ListShareFiles(shareCode, receiveCode, dirID string) ([]ShareFile, error) {
+	if isCalledByAlistV3() {
+		return nil, ErrorNotSupportAlist
+	}
+	var files []ShareFile
+	for offset := 0; ; offset += ShareSnapLimit {
+		result, err := c.GetShareSnap(shareCode, receiveCode, dirID, QueryLimit(ShareSnapLimit), QueryOffset(offset))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, result.Data.List...)
+		if len(result.Data.List) == 0 || offset+ShareSnapLimit >= result.Data.Count {
+			break
+		}
+	}
+	return files, nil
+}
+
+// ReceiveShare save shared files or directories into target directory, receives all when fileIDs is empty.
+// The receive API does not return ids of created files, so the returned ids are a best-effort guess:
+// target directory is listed before and after receiving, and everything new in it is returned,
+// including files added by other clients in between. It costs two full listings of target directory.
+// If the second listing fails, ids are nil and the error of receiving is returned, or the listing error
+// is wrapped when receiving succeeded.
+func (c *Pan115Client) ReceiveShare(shareCode, receiveCode string, fileIDs []string, targetDirID string, opts ...BatchOption) ([]string, error) {
+	if isCalledByAlistV3() {
+		return nil, ErrorNotSupportAlist
+	}
+	if err := c.ensureUserID(); err != nil {
+		return nil, err
+	}
+	if len(fileIDs) == 0 {
+		files, err := c.ListShareFiles(shareCode, receiveCode, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			fileIDs = append(fileIDs, f.ID())
+		}
+	}
+	if len(fileIDs) == 0 {
+		return nil, nil
+	}
+
+	before, err := c.List(targetDirID)
+	if err != nil {
+		return nil, err
+	}
+	existed := make(map[string]struct{}, len(*before))
+	for _, f := range *before {
+		existed[f.FileID] = struct{}{}
+	}
+
+	result := runBatch(fileIDs, func(ids []string) error {
+		return c.receiveShare(shareCode, receiveCode, ids, targetDirID)
+	}, opts...)
+
+	after, err := c.List(targetDirID)
+	if err != nil {
+		if batchErr := result.Err(); batchErr != nil {
+			return nil, batchErr
+		}
+		return nil, errors.Wrap(err, "received but could not enumerate target directory")
+	}
+	var created []string
+	for _, f := range *after {
+		if _, ok := existed[f.FileID]; !ok {
+			created = append(created, f.FileID)
+		}
+	}
+	return created, result.Err()
+}
+
+func (c *Pan115Client) receiveShare(shareCode, receiveCode string, fileIDs []string, targetDirID string) error {
+	result := BasicResp{}
+	req := c.NewRequest().
+		SetFormData(map[string]string{
+			"user_id":      strconv.FormatInt(c.UserID, 10),
+			"share_code":   shareCode,
+			"receive_code": receiveCode,
+			"file_id":      strings.Join(fileIDs, ","),
+			"cid":          targetDirID,
+			"is_check":     "0",
+		}).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Post(ApiShareReceive)
+	return CheckErr(err, &result, resp)
+}
@@ -0,0 +1,231 @@
+package driver
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ShareFS is a read-only fs.FS over a share, directory listings are cached.
+type ShareFS struct {
+	client      *Pan115Client
+	shareCode   string
+	receiveCode string
+	// err fails every operation
+	err error
+
+	mu    sync.Mutex
+	cache map[string][]ShareFile
+}
+
+var (
+	_ fs.FS        = (*ShareFS)(nil)
+	_ fs.ReadDirFS = (*ShareFS)(nil)
+	_ fs.StatFS    = (*ShareFS)(nil)
+)
+
+// ShareFS returns a read-only fs.FS over a share
+func (c *Pan115Client) ShareFS(shareCode, receiveCode string) *ShareFS {
+	fsys := &ShareFS{
+		client:      c,
+		shareCode:   shareCode,
+		receiveCode: receiveCode,
+		cache:       map[string][]ShareFile{},
+	}
+	if isCalledByAlistV3() {
+		fsys.err = ErrorNotSupportAlist
+	}
+	return fsys
+}
+
+// Open implements fs.FS
+func (fsys *ShareFS) Open(name string) (fs.File, error) {
+	info, err := fsys.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		entries, err := fsys.readDir("open", name, info.file.FileID)
+		if err != nil {
+			return nil, err
+		}
+		return &shareDir{info: info, entries: entries}, nil
+	}
+	return &shareFSFile{info: info, fsys: fsys}, nil
+}
+
+// Stat implements fs.StatFS
+func (fsys *ShareFS) Stat(name string) (fs.FileInfo, error) {
+	info, err := fsys.stat("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// ReadDir implements fs.ReadDirFS
+func (fsys *ShareFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	info, err := fsys.stat("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return fsys.readDir("readdir", name, info.file.FileID)
+}
+
+func (fsys *ShareFS) stat(op, name string) (*shareFileInfo, error) {
+	if fsys.err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fsys.err}
+	}
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	info := &shareFileInfo{file: File{IsDirectory: true, Name: "."}}
+	if name == "." {
+		return info, nil
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if !info.IsDir() {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		files, err := fsys.list(info.file.FileID)
+		if err != nil {
+			return nil, &fs.PathError{Op: op, Path: name, Err: err}
+		}
+		var found *shareFileInfo
+		for i := range files {
+			if files[i].FileName == elem {
+				found = &shareFileInfo{file: *(&File{}).FromShareFile(&files[i])}
+				break
+			}
+		}
+		if found == nil {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		info = found
+	}
+	return info, nil
+}
+
+func (fsys *ShareFS) readDir(op, name, dirID string) ([]fs.DirEntry, error) {
+	files, err := fsys.list(dirID)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	entries := make([]fs.DirEntry, len(files))
+	for i := range files {
+		entries[i] = &shareFileInfo{file: *(&File{}).FromShareFile(&files[i])}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+func (fsys *ShareFS) list(dirID string) ([]ShareFile, error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+	if files, ok := fsys.cache[dirID]; ok {
+		return files, nil
+	}
+	files, err := fsys.client.ListShareFiles(fsys.shareCode, fsys.receiveCode, dirID)
+	if err != nil {
+		return nil, err
+	}
+	fsys.cache[dirID] = files
+	return files, nil
+}
+
+// shareFileInfo implements fs.FileInfo and fs.DirEntry
+type shareFileInfo struct {
+	file File
+}
+
+func (i *shareFileInfo) Name() string               { return i.file.Name }
+func (i *shareFileInfo) Size() int64                { return i.file.Size }
+func (i *shareFileInfo) ModTime() time.Time         { return i.file.UpdateTime }
+func (i *shareFileInfo) IsDir() bool                { return i.file.IsDirectory }
+func (i *shareFileInfo) Sys() any                   { return &i.file }
+func (i *shareFileInfo) Type() fs.FileMode          { return i.Mode().Type() }
+func (i *shareFileInfo) Info() (fs.FileInfo, error) { return i, nil }
+
+func (i *shareFileInfo) Mode() fs.FileMode {
+	if i.file.IsDirectory {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+type shareDir struct {
+	info    *shareFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *shareDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *shareDir) Close() error               { return nil }
+
+func (d *shareDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: ErrDownloadDirectory}
+}
+
+// ReadDir implements fs.ReadDirFile
+func (d *shareDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+	return rest[:n], nil
+}
+
+// shareFSFile streams content of a shared file, the download url is fetched on first read
+type shareFSFile struct {
+	info *shareFileInfo
+	fsys *ShareFS
+	body io.ReadCloser
+}
+
+func (f *shareFSFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+func (f *shareFSFile) Read(b []byte) (int, error) {
+	if f.body == nil {
+		u, err := shareDownloadURL(f.fsys.client, f.fsys.shareCode, f.fsys.receiveCode, f.info.file.FileID)
+		if err != nil {
+			return 0, err
+		}
+		resp, err := f.fsys.client.NewRequest().
+			SetDoNotParseResponse(true).
+			Get(u)
+		if err != nil {
+			return 0, err
+		}
+		if resp.StatusCode() != http.StatusOK {
+			resp.RawBody().Close()
+			return 0, &fs.PathError{Op: "read", Path: f.info.file.Name, Err: fmt.Errorf("download: %s", resp.Status())}
+		}
+		f.body = resp.RawBody()
+	}
+	return f.body.Read(b)
+}
+
+func (f *shareFSFile) Close() error {
+	if f.body == nil {
+		return nil
+	}
+	return f.body.Close()
+}
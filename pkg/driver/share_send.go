@@ -0,0 +1,175 @@
+package driver
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ShareOption set share settings
+type ShareOption func(form map[string]string)
+
+const (
+	// ShareDurationForever never expires
+	ShareDurationForever = -1
+)
+
+// ShareWithDuration set share duration in days, ShareDurationForever for never expires
+func ShareWithDuration(days int) ShareOption {
+	return func(form map[string]string) {
+		form["share_duration"] = strconv.Itoa(days)
+	}
+}
+
+// ShareWithReceiveCode set custom receive code, empty code means no receive code
+func ShareWithReceiveCode(code string) ShareOption {
+	return func(form map[string]string) {
+		form["receive_code"] = code
+		form["is_custom_code"] = "1"
+		if code == "" {
+			form["is_custom_code"] = "0"
+		}
+	}
+}
+
+// ShareWithAutoRenewal renew the share automatically when it expires
+func ShareWithAutoRenewal(e bool) ShareOption {
+	return func(form map[string]string) {
+		form["auto_renewal"] = strconv.Itoa(BoolToInt(e))
+	}
+}
+
+// ShareWithAutoFillRecvcode fill receive code automatically when the share url is opened
+func ShareWithAutoFillRecvcode(e bool) ShareOption {
+	return func(form map[string]string) {
+		form["auto_fill_recvcode"] = strconv.Itoa(BoolToInt(e))
+	}
+}
+
+// CreateShare create a share of files or directories. The share is canceled if applying opts fails,
+// when canceling fails too, the created share is returned with the error.
+func (c *Pan115Client) CreateShare(fileIDs []string, opts ...ShareOption) (*ShareInfo, error) {
+	if err := c.ensureUserID(); err != nil {
+		return nil, err
+	}
+	result := ShareInfoResp{}
+	req := c.NewRequest().
+		SetFormData(map[string]string{
+			"user_id":     strconv.FormatInt(c.UserID, 10),
+			"file_ids":    strings.Join(fileIDs, ","),
+			"ignore_warn": "1",
+			"is_asc":      "1",
+			"order":       "file_name",
+		}).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Post(ApiShareSend)
+	if err = CheckErr(err, &result, resp); err != nil {
+		return nil, err
+	}
+	if len(opts) == 0 {
+		return &result.Data, nil
+	}
+
+	shareCode := result.Data.ShareCode
+	if err := c.UpdateShare(shareCode, opts...); err != nil {
+		// do not leave a share with default settings
+		if cancelErr := c.CancelShares(shareCode); cancelErr != nil {
+			return &result.Data, errors.Wrapf(err, "share %s is left with default settings, cancel: %v", shareCode, cancelErr)
+		}
+		return nil, err
+	}
+	info, err := c.GetShareInfo(shareCode)
+	if err != nil {
+		return &result.Data, err
+	}
+	return info, nil
+}
+
+// UpdateShare update settings of a share
+func (c *Pan115Client) UpdateShare(shareCode string, opts ...ShareOption) error {
+	form := map[string]string{
+		"share_code": shareCode,
+	}
+	for _, opt := range opts {
+		opt(form)
+	}
+	return c.updateShare(form)
+}
+
+// CancelShares cancel shares
+func (c *Pan115Client) CancelShares(shareCodes ...string) error {
+	if len(shareCodes) == 0 {
+		return nil
+	}
+	return c.updateShare(map[string]string{
+		"share_code": strings.Join(shareCodes, ","),
+		"action":     "cancel",
+	})
+}
+
+func (c *Pan115Client) updateShare(form map[string]string) error {
+	result := BasicResp{}
+	req := c.NewRequest().
+		SetFormData(form).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Post(ApiShareUpdate)
+	return CheckErr(err, &result, resp)
+}
+
+// GetShareInfo get info of my share
+func (c *Pan115Client) GetShareInfo(shareCode string) (*ShareInfo, error) {
+	result := ShareInfoResp{}
+	req := c.NewRequest().
+		SetQueryParam("share_code", shareCode).
+		ForceContentType("application/json;charset=UTF-8").
+		SetResult(&result)
+	resp, err := req.Get(ApiShareInfo)
+	if err = CheckErr(err, &result, resp); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+const ShareListLimit = 100
+
+// ListShares list all my shares
+func (c *Pan115Client) ListShares() ([]*ShareInfo, error) {
+	if err := c.ensureUserID(); err != nil {
+		return nil, err
+	}
+	var shares []*ShareInfo
+	for offset := 0; ; offset += ShareListLimit {
+		result := ShareListResp{}
+		req := c.NewRequest().
+			SetQueryParams(map[string]string{
+				"user_id": strconv.FormatInt(c.UserID, 10),
+				"offset":  strconv.Itoa(offset),
+				"limit":   strconv.Itoa(ShareListLimit),
+			}).
+			ForceContentType("application/json;charset=UTF-8").
+			SetResult(&result)
+		resp, err := req.Get(ApiShareList)
+		if err = CheckErr(err, &result, resp); err != nil {
+			return nil, err
+		}
+		shares = append(shares, result.List...)
+		if len(result.List) == 0 || offset+ShareListLimit >= result.Count {
+			break
+		}
+	}
+	return shares, nil
+}
+
+type ShareInfoResp struct {
+	BasicResp
+	Data ShareInfo `json:"data"`
+}
+
+type ShareListResp struct {
+	BasicResp
+	Count int          `json:"count"`
+	List  []*ShareInfo `json:"list"`
+}
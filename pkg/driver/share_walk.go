@@ -0,0 +1,101 @@
+package driver
+
+import (
+	"context"
+	"io/fs"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	shareCodeRegexp   = regexp.MustCompile(`/s/([0-9a-zA-Z]+)`)
+	receiveCodeRegexp = regexp.MustCompile(`(?:password=|访问码[:：]?\s*|提取码[:：]?\s*)([0-9a-zA-Z]{4})`)
+	shareURLRegexp    = regexp.MustCompile(`https?://[^\s]+`)
+)
+
+// ParseShareURL parse share code and receive code from share url like https://115.com/s/xxxx?password=yyyy,
+// the share text copied from 115 is also supported.
+func ParseShareURL(link string) (shareCode, receiveCode string, err error) {
+	link = strings.TrimSpace(link)
+	rawURL := shareURLRegexp.FindString(link)
+	if rawURL == "" {
+		return "", "", errors.Wrap(ErrSharedInvalid, link)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", errors.Wrap(ErrSharedInvalid, err.Error())
+	}
+	m := shareCodeRegexp.FindStringSubmatch(u.Path)
+	if m == nil {
+		return "", "", errors.Wrap(ErrSharedInvalid, link)
+	}
+	shareCode = m[1]
+	receiveCode = u.Query().Get("password")
+	if receiveCode == "" {
+		if m := receiveCodeRegexp.FindStringSubmatch(link); m != nil {
+			receiveCode = m[1]
+		}
+	}
+	return shareCode, receiveCode, nil
+}
+
+// WalkShareFunc is called for every file and directory in a share, filePath is relative to the share root.
+// Returning fs.SkipDir on a directory skips it, returning fs.SkipAll stops the walk.
+type WalkShareFunc func(filePath string, file *ShareFile) error
+
+// WalkShare walks all files and directories in a share link recursively
+func (c *Pan115Client) WalkShare(ctx context.Context, link string, fn WalkShareFunc) error {
+	if isCalledByAlistV3() {
+		return ErrorNotSupportAlist
+	}
+	shareCode, receiveCode, err := ParseShareURL(link)
+	if err != nil {
+		return err
+	}
+	return c.WalkShareByCode(ctx, shareCode, receiveCode, fn)
+}
+
+// WalkShareByCode walks all files and directories in a share recursively
+func (c *Pan115Client) WalkShareByCode(ctx context.Context, shareCode, receiveCode string, fn WalkShareFunc) error {
+	if isCalledByAlistV3() {
+		return ErrorNotSupportAlist
+	}
+	err := c.walkShare(ctx, shareCode, receiveCode, "", "", fn)
+	if errors.Is(err, fs.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+func (c *Pan115Client) walkShare(ctx context.Context, shareCode, receiveCode, dirID, dirPath string, fn WalkShareFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	files, err := c.ListShareFiles(shareCode, receiveCode, dirID)
+	if err != nil {
+		return err
+	}
+	for i := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		f := &files[i]
+		filePath := path.Join(dirPath, f.FileName)
+		if err := fn(filePath, f); err != nil {
+			if f.IsDirectory() && errors.Is(err, fs.SkipDir) {
+				continue
+			}
+			return err
+		}
+		if f.IsDirectory() {
+			if err := c.walkShare(ctx, shareCode, receiveCode, f.ID(), filePath, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package driver
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TorrentFile describes a file in a torrent
+type TorrentFile struct {
+	// Index is the position of the file in the torrent, used to select files to download.
+	Index int
+	// Path is the relative path of the file, with slash separator.
+	Path string
+	Size int64
+}
+
+// Torrent describes a parsed torrent
+type Torrent struct {
+	Name     string
+	InfoHash string
+	Size     int64
+	Files    []TorrentFile
+}
+
+// ParseTorrent parses a .torrent file locally
+func ParseTorrent(r io.Reader) (*Torrent, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	d := &bencodeDecoder{data: data}
+	v, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	root, ok := v.(map[string]any)
+	if !ok {
+		return nil, errors.Wrap(ErrBadTorrent, "root is not a dict")
+	}
+	info, ok := root["info"].(map[string]any)
+	if !ok {
+		return nil, errors.Wrap(ErrBadTorrent, "missing info")
+	}
+	hash := sha1.Sum(data[d.infoStart:d.infoEnd])
+
+	t := &Torrent{
+		Name:     bencodeUTF8String(info, "name"),
+		InfoHash: hex.EncodeToString(hash[:]),
+	}
+	files, ok := info["files"].([]any)
+	if !ok {
+		size, _ := info["length"].(int64)
+		t.Size = size
+		t.Files = []TorrentFile{{Path: t.Name, Size: size}}
+		return t, nil
+	}
+	for i, item := range files {
+		file, ok := item.(map[string]any)
+		if !ok {
+			return nil, errors.Wrap(ErrBadTorrent, "bad file "+strconv.Itoa(i))
+		}
+		elems, ok := file["path.utf-8"].([]any)
+		if !ok {
+			elems, _ = file["path"].([]any)
+		}
+		parts := make([]string, 0, len(elems))
+		for _, elem := range elems {
+			if s, ok := elem.(string); ok {
+				parts = append(parts, s)
+			}
+		}
+		size, _ := file["length"].(int64)
+		t.Size += size
+		t.Files = append(t.Files, TorrentFile{
+			Index: i,
+			Path:  path.Join(parts...),
+			Size:  size,
+		})
+	}
+	return t, nil
+}
+
+func bencodeUTF8String(dict map[string]any, key string) string {
+	if s, ok := dict[key+".utf-8"].(string); ok {
+		return s
+	}
+	s, _ := dict[key].(string)
+	return s
+}
+
+// UploadTorrent upload a .torrent file into directory, returns sha1 of the torrent file
+func (c *Pan115Client) UploadTorrent(dirID, fileName string, r io.ReadSeeker) (string, error) {
+	digest, err := c.GetDigestResult(r)
+	if err != nil {
+		return "", err
+	}
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	if err = c.RapidUploadOrByOSS(dirID, fileName, digest.Size, r); err != nil {
+		return "", err
+	}
+	return digest.QuickID, nil
+}
+
+// GetOfflineTorrentInfo get torrent info parsed by server with sha1 of an uploaded torrent file
+func (c *Pan115Client) GetOfflineTorrentInfo(sha1 string) (*Torrent, error) {
+	result := OfflineTorrentInfoResp{}
+	req := c.NewRequest().
+		SetFormData(map[string]string{"sha1": sha1}).
+		SetResult(&result).
+		ForceContentType("application/json;charset=UTF-8")
+
+	resp, err := req.Post(ApiOfflineTorrentInfo)
+	if err := CheckErr(err, &result, resp); err != nil {
+		return nil, err
+	}
+	t := &Torrent{
+		Name:     result.TorrentName,
+		InfoHash: result.InfoHash,
+		Size:     int64(result.FileSize),
+		Files:    make([]TorrentFile, len(result.Files)),
+	}
+	for i, f := range result.Files {
+		t.Files[i] = TorrentFile{
+			Index: i,
+			Path:  f.Path,
+			Size:  int64(f.Size),
+		}
+	}
+	return t, nil
+}
+
+// AddOfflineTaskTorrent adds an offline task by info hash of a torrent parsed by server,
+// wanted is the indexes of files to download, empty means all files.
+func (c *Pan115Client) AddOfflineTaskTorrent(t *Torrent, wanted []int, saveDirID string, opts ...OfflineOption) (string, error) {
+	if isCalledByAlistV3() {
+		return "", ErrorNotSupportAlist
+	}
+	if len(wanted) == 0 {
+		for _, f := range t.Files {
+			wanted = append(wanted, f.Index)
+		}
+	}
+	indexes := make([]string, len(wanted))
+	for i, index := range wanted {
+		indexes[i] = strconv.Itoa(index)
+	}
+	params := map[string]string{
+		"ac":         "add_task_bt",
+		"info_hash":  t.InfoHash,
+		"wanted":     strings.Join(indexes, ","),
+		"savepath":   t.Name,
+		"wp_path_id": saveDirID,
+	}
+
+	result := OfflineAddTorrentResponse{}
+	if err := c.postLixianSSP(ApiAddOfflineBt, params, &result, opts...); err != nil {
+		return "", err
+	}
+	if err := result.Err(); err != nil {
+		return "", err
+	}
+	return result.InfoHash, nil
+}
+
+// AddOfflineTaskTorrentFile uploads a local .torrent file and adds an offline task with files chosen by selector,
+// nil selector chooses all files. The torrent file is uploaded into saveDirID unless WithTorrentDirID is set.
+func (c *Pan115Client) AddOfflineTaskTorrentFile(torrentPath, saveDirID string, selector func(f *TorrentFile) bool, opts ...OfflineOption) (string, error) {
+	if isCalledByAlistV3() {
+		return "", ErrorNotSupportAlist
+	}
+	opt := DefaultOfflineOptions()
+	for _, o := range opts {
+		o(&opt)
+	}
+	torrentDirID := opt.torrentDirID
+	if torrentDirID == "" {
+		torrentDirID = saveDirID
+	}
+
+	f, err := os.Open(torrentPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	// parse locally to reject bad torrent before uploading
+	if _, err = ParseTorrent(f); err != nil {
+		return "", err
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	sha1, err := c.UploadTorrent(torrentDirID, path.Base(torrentPath), f)
+	if err != nil {
+		return "", err
+	}
+	t, err := c.GetOfflineTorrentInfo(sha1)
+	if err != nil {
+		return "", err
+	}
+	var wanted []int
+	for i := range t.Files {
+		if selector == nil || selector(&t.Files[i]) {
+			wanted = append(wanted, t.Files[i].Index)
+		}
+	}
+	if len(wanted) == 0 {
+		return "", errors.Wrap(ErrWrongParams, "no file is selected")
+	}
+	return c.AddOfflineTaskTorrent(t, wanted, saveDirID, opts...)
+}
+
+type OfflineTorrentInfoResp struct {
+	BasicResp
+	TorrentName string      `json:"torrent_name"`
+	InfoHash    string      `json:"info_hash"`
+	FileSize    StringInt64 `json:"file_size"`
+	FileCount   int         `json:"file_count"`
+	Files       []struct {
+		Size   StringInt64 `json:"size"`
+		Path   string      `json:"path"`
+		Wanted int         `json:"wanted"`
+	} `json:"torrent_filelist_web"`
+}
+
+type OfflineAddTorrentResponse struct {
+	BasicResp
+	ErrCode  int    `json:"errcode"`
+	ErrorMsg string `json:"error_msg"`
+	InfoHash string `json:"info_hash"`
+	Name     string `json:"name"`
+}
+
+func (resp *OfflineAddTorrentResponse) Err(respBody ...string) error {
+	if resp.State {
+		return nil
+	}
+	return GetErr(findNonZero(resp.ErrCode, int(resp.Errno), resp.ErrNo), resp.ErrorMsg)
+}
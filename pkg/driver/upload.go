@@ -2,6 +2,7 @@ package driver
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"crypto/sha1"
 	"encoding/base64"
@@ -42,18 +43,28 @@ func (c *Pan115Client) GetUploadEndpoint(endpoint *UploadEndpointResp) error {
 
 // GetUploadInfo get some info for upload
 func (c *Pan115Client) GetUploadInfo() error {
+	result, err := c.getUploadInfo(context.Background())
+	if err != nil {
+		return err
+	}
+	c.Userkey = result.Userkey
+	c.UserID = result.UserID
+	c.UploadMetaInfo = &result.UploadMetaInfo
+	return nil
+}
+
+// getUploadInfo fetches upload info without changing the client
+func (c *Pan115Client) getUploadInfo(ctx context.Context) (*UploadInfoResp, error) {
 	result := UploadInfoResp{}
 	req := c.NewRequest().
+		SetContext(ctx).
 		ForceContentType("application/json;charset=UTF-8").
 		SetResult(&result)
 	resp, err := req.Post(ApiUploadInfo)
 	if err = CheckErr(err, &result, resp); err != nil {
-		return err
+		return nil, err
 	}
-	c.Userkey = result.Userkey
-	c.UserID = result.UserID
-	c.UploadMetaInfo = &result.UploadMetaInfo
-	return nil
+	return &result, nil
 }
 
 // UploadAvailable check and prepare to upload